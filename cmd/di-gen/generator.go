@@ -0,0 +1,945 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	diPath     = "github.com/go-path/di"
+	ditestPath = "github.com/go-path/di/ditest"
+)
+
+// generatedHeader the first line of the files generated by di-gen
+const generatedHeader = "// Code generated by di-gen. DO NOT EDIT."
+
+// pkg a parsed and type-checked package
+type pkg struct {
+	fset  *token.FileSet
+	files []*ast.File
+	types *types.Package
+	info  *types.Info
+	deps  []*pkg // the packages of the same module imported by the package, transitively
+}
+
+// load parses and type-checks the package in dir, ignoring the generated file. The
+// packages of the same module imported by the package are loaded from source too, their
+// registrations are part of the graph (see generator.collect).
+func load(dir, output string) (*pkg, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	fset := token.NewFileSet()
+	l := &loader{
+		fset: fset,
+		info: &types.Info{
+			Types:     map[ast.Expr]types.TypeAndValue{},
+			Uses:      map[*ast.Ident]types.Object{},
+			Instances: map[*ast.Ident]types.Instance{},
+		},
+		fallback: importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+		pkgs:     map[string]*pkg{},
+	}
+	l.modPath, l.modDir = module(dir)
+
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	p, err := l.load(dir, importPath(l.modPath, l.modDir, dir, bp.Name), output, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, dep := range l.loaded {
+		if dep != p {
+			p.deps = append(p.deps, dep)
+		}
+	}
+	return p, nil
+}
+
+// loader loads the packages of the module from source, sharing the type information.
+// The other packages (and the di packages) are imported by the source importer.
+type loader struct {
+	fset     *token.FileSet
+	info     *types.Info
+	fallback types.ImporterFrom
+	modPath  string
+	modDir   string
+	pkgs     map[string]*pkg // by import path
+	loaded   []*pkg          // in load order, dependencies first
+}
+
+// load parses and type-checks the package in dir, skipping the output file. The files
+// generated by di-gen are skipped in the dependencies, they may be outdated.
+func (l *loader) load(dir, path, output string, dep bool) (*pkg, error) {
+	if p, exists := l.pkgs[path]; exists {
+		return p, nil
+	}
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &pkg{fset: l.fset, info: l.info}
+	names := append([]string{}, bp.GoFiles...)
+	sort.Strings(names)
+	for _, name := range names {
+		if name == output {
+			continue
+		}
+		file, err := parser.ParseFile(l.fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if !dep || !isGenerated(file) {
+			p.files = append(p.files, file)
+		}
+	}
+
+	conf := types.Config{Importer: l}
+	if p.types, err = conf.Check(path, l.fset, p.files, l.info); err != nil {
+		return nil, err
+	}
+	l.pkgs[path] = p
+	l.loaded = append(l.loaded, p)
+	return p, nil
+}
+
+func (l *loader) Import(path string) (*types.Package, error) {
+	return l.ImportFrom(path, "", 0)
+}
+
+func (l *loader) ImportFrom(path, dir string, mode types.ImportMode) (*types.Package, error) {
+	if rel, local := l.local(path); local {
+		p, err := l.load(filepath.Join(l.modDir, filepath.FromSlash(rel)), path, "", true)
+		if err != nil {
+			return nil, err
+		}
+		return p.types, nil
+	}
+	return l.fallback.ImportFrom(path, dir, mode)
+}
+
+// local the path of the package relative to the module root, if the package belongs to
+// the module (the di packages are never loaded as part of the graph)
+func (l *loader) local(path string) (string, bool) {
+	if l.modPath == "" || path == diPath || path == ditestPath {
+		return "", false
+	}
+	if path == l.modPath {
+		return ".", true
+	}
+	rel, ok := strings.CutPrefix(path, l.modPath+"/")
+	return rel, ok
+}
+
+// isGenerated checks if the file was generated by di-gen
+func isGenerated(file *ast.File) bool {
+	return len(file.Comments) > 0 && file.Comments[0].Pos() < file.Package &&
+		file.Comments[0].List[0].Text == generatedHeader
+}
+
+// module the path and the directory of the nearest go.mod
+func module(dir string) (string, string) {
+	for modDir := dir; ; {
+		if data, err := os.ReadFile(filepath.Join(modDir, "go.mod")); err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if fields := strings.Fields(line); len(fields) == 2 && fields[0] == "module" {
+					return strings.Trim(fields[1], `"`), modDir
+				}
+			}
+		}
+		parent := filepath.Dir(modDir)
+		if parent == modDir {
+			return "", ""
+		}
+		modDir = parent
+	}
+}
+
+// importPath the import path of the package in dir (absolute)
+func importPath(modPath, modDir, dir, name string) string {
+	if modPath == "" {
+		return name
+	}
+	rel, _ := filepath.Rel(modDir, dir)
+	return path.Join(modPath, filepath.ToSlash(rel))
+}
+
+// check type-checks the package files
+func (p *pkg) check(path string) error {
+	conf := types.Config{Importer: importer.ForCompiler(p.fset, "source", nil)}
+	var err error
+	p.types, err = conf.Check(path, p.fset, p.files, p.info)
+	return err
+}
+
+// wiring a constructor registered in the graph, called by the generated code
+type wiring struct {
+	fn  *types.Func
+	pos token.Pos
+}
+
+// injector a struct registered with Injector, Injected or InjectedTo
+type injector struct {
+	typ types.Type
+	pos token.Pos
+}
+
+// provider a component registered in the graph
+type provider struct {
+	typ types.Type
+	fn  *types.Func // the constructor, nil for instances, function literals and injectors
+}
+
+type generator struct {
+	pkg       *pkg
+	ctors     []*wiring // constructors registered in the graph
+	wirings   []*wiring // constructors called by the generated code
+	injectors []*injector
+	provided  []*provider // components registered in the graph
+	imports   map[string]string
+	names     map[string]string
+}
+
+func newGenerator(p *pkg) *generator {
+	return &generator{
+		pkg:     p,
+		imports: map[string]string{},
+		names:   map[string]string{},
+	}
+}
+
+// files the files of the graph: the package and its dependencies in the module
+func (g *generator) files() []*ast.File {
+	files := append([]*ast.File{}, g.pkg.files...)
+	for _, dep := range g.pkg.deps {
+		files = append(files, dep.files...)
+	}
+	return files
+}
+
+// collect finds the registrations of the graph
+func (g *generator) collect() {
+	seenFuncs := map[*types.Func]bool{}
+	seenTypes := map[string]bool{}
+
+	addInjector := func(t types.Type, pos token.Pos) {
+		g.provided = append(g.provided, &provider{typ: t})
+		key := types.TypeString(t, nil)
+		if seenTypes[key] || !g.injectable(t) {
+			return
+		}
+		seenTypes[key] = true
+		g.injectors = append(g.injectors, &injector{typ: t, pos: pos})
+	}
+
+	for _, file := range g.files() {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			fn, typeArgs := g.diFunc(call.Fun)
+			switch fn {
+			case "Register", "ShouldRegister":
+				if len(call.Args) == 0 {
+					return true
+				}
+				arg := unparen(call.Args[0])
+				if ctor := g.funcOf(arg); ctor != nil {
+					g.provide(ctor.Type().(*types.Signature), ctor)
+					if !seenFuncs[ctor] {
+						seenFuncs[ctor] = true
+						w := &wiring{fn: ctor, pos: call.Pos()}
+						g.ctors = append(g.ctors, w)
+						if g.invokable(ctor) {
+							g.wirings = append(g.wirings, w)
+						}
+					}
+				} else if t := g.pkg.info.TypeOf(arg); t != nil {
+					if sig, ok := t.Underlying().(*types.Signature); ok {
+						g.provide(sig, nil)
+					} else {
+						g.provided = append(g.provided, &provider{typ: t})
+					}
+				}
+			case "Injector", "Injected", "InjectedTo":
+				if typeArgs != nil && typeArgs.Len() == 1 {
+					addInjector(typeArgs.At(0), call.Pos())
+				}
+			}
+			return true
+		})
+	}
+}
+
+// provide registers the value returned by the constructor as provided
+func (g *generator) provide(sig *types.Signature, fn *types.Func) {
+	if valueIdx, _, ok := resultShape(sig); ok && valueIdx >= 0 {
+		g.provided = append(g.provided, &provider{typ: sig.Results().At(valueIdx).Type(), fn: fn})
+	}
+}
+
+// providersOf the components selected by the container for the dependency: the exact
+// matches or, if none, the assignable components
+func (g *generator) providersOf(dep types.Type) []*provider {
+	var exact, assignable []*provider
+	seen := map[*types.Func]bool{}
+	for _, p := range g.provided {
+		if p.fn != nil {
+			if seen[p.fn] {
+				// registered more than once (ex. in different containers)
+				continue
+			}
+			seen[p.fn] = true
+		}
+		if types.Identical(p.typ, dep) {
+			exact = append(exact, p)
+		} else if types.AssignableTo(p.typ, dep) {
+			assignable = append(assignable, p)
+		}
+	}
+	if len(exact) > 0 {
+		return exact
+	}
+	return assignable
+}
+
+// diFunc the name and type arguments of a function (or Container method) of the di package
+func (g *generator) diFunc(fun ast.Expr) (string, *types.TypeList) {
+	var ident *ast.Ident
+	switch e := unparen(fun).(type) {
+	case *ast.IndexExpr:
+		fun = e.X
+	case *ast.IndexListExpr:
+		fun = e.X
+	}
+	switch e := unparen(fun).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return "", nil
+	}
+
+	obj, ok := g.pkg.info.Uses[ident].(*types.Func)
+	if !ok || obj.Pkg() == nil || obj.Pkg().Path() != diPath {
+		return "", nil
+	}
+	return obj.Name(), g.pkg.info.Instances[ident].TypeArgs
+}
+
+// funcOf the top-level function referenced by the expression, if any
+func (g *generator) funcOf(expr ast.Expr) *types.Func {
+	var ident *ast.Ident
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return nil
+	}
+	fn, ok := g.pkg.info.Uses[ident].(*types.Func)
+	if !ok {
+		return nil
+	}
+	if sig := fn.Type().(*types.Signature); sig.Recv() != nil {
+		return nil
+	}
+	return fn
+}
+
+// invokable checks if the generated code can call the constructor
+func (g *generator) invokable(fn *types.Func) bool {
+	sig := fn.Type().(*types.Signature)
+	if sig.Variadic() || sig.TypeParams().Len() > 0 || !g.accessible(fn) {
+		return false
+	}
+	if _, _, ok := resultShape(sig); !ok {
+		return false
+	}
+	for i := 0; i < sig.Params().Len(); i++ {
+		if !g.nameable(sig.Params().At(i).Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// injectable checks if the generated code can create the struct (or *struct)
+func (g *generator) injectable(t types.Type) bool {
+	st := structOf(t)
+	if st == nil || !g.nameable(t) {
+		return false
+	}
+	for _, field := range injectFields(st) {
+		if !g.nameable(field.Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// accessible checks if the object can be referenced by the generated code
+func (g *generator) accessible(obj types.Object) bool {
+	return obj.Pkg() == g.pkg.types || obj.Exported()
+}
+
+// nameable checks if the type can be written in the generated code
+func (g *generator) nameable(t types.Type) (ok bool) {
+	ok = true
+	var visit func(t types.Type)
+	seen := map[types.Type]bool{}
+	visit = func(t types.Type) {
+		if !ok || seen[t] {
+			return
+		}
+		seen[t] = true
+		switch t := t.(type) {
+		case *types.Named:
+			if obj := t.Obj(); obj.Pkg() != nil && (!g.accessible(obj) || obj.Parent() != obj.Pkg().Scope()) {
+				// unexported or declared inside a function
+				ok = false
+			}
+			if args := t.TypeArgs(); args != nil {
+				for i := 0; i < args.Len(); i++ {
+					visit(args.At(i))
+				}
+			}
+		case *types.Pointer:
+			visit(t.Elem())
+		case *types.Slice:
+			visit(t.Elem())
+		case *types.Array:
+			visit(t.Elem())
+		case *types.Map:
+			visit(t.Key())
+			visit(t.Elem())
+		case *types.Chan:
+			visit(t.Elem())
+		case *types.Signature:
+			for i := 0; i < t.Params().Len(); i++ {
+				visit(t.Params().At(i).Type())
+			}
+			for i := 0; i < t.Results().Len(); i++ {
+				visit(t.Results().At(i).Type())
+			}
+		}
+	}
+	visit(t)
+	return
+}
+
+// check verifies that every constructor parameter (and injected field) has a provider
+// registered in the graph.
+func (g *generator) check() error {
+	var errs []error
+	missing := func(pos token.Pos, owner string, t types.Type, field bool) {
+		t = dependencyOf(t)
+		if t == nil || len(g.providersOf(t)) > 0 {
+			return
+		}
+		if field && structOf(t) != nil {
+			// injected automatically (see di.InjectorOf)
+			return
+		}
+		errs = append(errs, fmt.Errorf("%v: %s: no provider registered for %s", g.pkg.fset.Position(pos), owner, types.TypeString(t, g.relative)))
+	}
+
+	for _, w := range g.ctors {
+		params := w.fn.Type().(*types.Signature).Params()
+		for i := 0; i < params.Len(); i++ {
+			missing(w.pos, g.funcName(w.fn), params.At(i).Type(), false)
+		}
+	}
+	for _, inj := range g.injectors {
+		for _, field := range injectFields(structOf(inj.typ)) {
+			missing(inj.pos, types.TypeString(inj.typ, g.relative)+"."+field.Name(), field.Type(), true)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// generate the source of the generated file
+func (g *generator) generate() ([]byte, error) {
+	var decls, body bytes.Buffer
+
+	for _, w := range g.wirings {
+		g.writeWiring(&body, w)
+	}
+	for _, inj := range g.injectors {
+		g.writeInjector(&body, inj)
+	}
+	g.writeProviders(&decls)
+
+	return g.source(nil, body.Bytes(), decls.Bytes())
+}
+
+// generateSpies the source of the spies file (see ditest.Spy): a proxy for each
+// interface provided or injected by the registrations of the package
+func (g *generator) generateSpies() ([]byte, error) {
+	var decls, body bytes.Buffer
+	for _, iface := range g.spyInterfaces() {
+		g.writeSpy(&decls, &body, iface)
+	}
+	return g.source(decls.Bytes(), body.Bytes(), nil)
+}
+
+// source the generated file, with the declarations, the body of init and the declarations
+// after init
+func (g *generator) source(decls, body, after []byte) ([]byte, error) {
+	var src bytes.Buffer
+	src.WriteString(generatedHeader + "\n\n")
+	fmt.Fprintf(&src, "package %s\n", g.pkg.types.Name())
+
+	if len(body) > 0 {
+		paths := make([]string, 0, len(g.imports))
+		for path := range g.imports {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+
+		src.WriteString("\nimport (\n")
+		for _, path := range paths {
+			name := g.imports[path]
+			if name == pathName(path) {
+				fmt.Fprintf(&src, "\t%q\n", path)
+			} else {
+				fmt.Fprintf(&src, "\t%s %q\n", name, path)
+			}
+		}
+		src.WriteString(")\n\n")
+		src.Write(decls)
+		src.WriteString("func init() {\n")
+		src.Write(body)
+		src.WriteString("}\n")
+		src.Write(after)
+	}
+
+	return format.Source(src.Bytes())
+}
+
+// spyInterfaces the interfaces provided or injected by the registrations of the package
+// that the generated code can implement
+func (g *generator) spyInterfaces() (ifaces []*types.Named) {
+	seen := map[string]bool{}
+	add := func(t types.Type) {
+		t = dependencyOf(t)
+		named, ok := t.(*types.Named)
+		if !ok || seen[types.TypeString(t, nil)] || !g.spyable(named) {
+			return
+		}
+		seen[types.TypeString(t, nil)] = true
+		ifaces = append(ifaces, named)
+	}
+
+	for _, p := range g.provided {
+		add(p.typ)
+	}
+	for _, w := range g.wirings {
+		params := w.fn.Type().(*types.Signature).Params()
+		for i := 0; i < params.Len(); i++ {
+			add(params.At(i).Type())
+		}
+	}
+	for _, inj := range g.injectors {
+		for _, field := range injectFields(structOf(inj.typ)) {
+			add(field.Type())
+		}
+	}
+
+	sort.Slice(ifaces, func(i, j int) bool {
+		return types.TypeString(ifaces[i], nil) < types.TypeString(ifaces[j], nil)
+	})
+	return
+}
+
+// spyable checks if the generated code can implement the interface
+func (g *generator) spyable(named *types.Named) bool {
+	iface, ok := named.Underlying().(*types.Interface)
+	if !ok || iface.NumMethods() == 0 || !iface.IsMethodSet() || named.TypeArgs() != nil || !g.nameable(named) {
+		return false
+	}
+	if obj := named.Obj(); obj.Pkg() == nil {
+		// error
+		return false
+	}
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		if !g.accessible(method) || !g.nameable(method.Type()) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeSpy the proxy of the interface, which records the calls and forwards them to
+// the real component
+func (g *generator) writeSpy(decls, body *bytes.Buffer, named *types.Named) {
+	typeName := g.typeString(named)
+	name := g.spyName(named)
+	recorder := "*" + g.importName(ditestPath, "ditest") + ".Recorder"
+
+	fmt.Fprintf(decls, "type %s struct {\ntarget %s\nrecorder %s\n}\n\n", name, typeName, recorder)
+
+	iface := named.Underlying().(*types.Interface)
+	for i := 0; i < iface.NumMethods(); i++ {
+		method := iface.Method(i)
+		sig := method.Type().(*types.Signature)
+
+		params := make([]string, sig.Params().Len())
+		args := make([]string, len(params))
+		for j := range params {
+			t := sig.Params().At(j).Type()
+			args[j] = fmt.Sprintf("a%d", j)
+			if sig.Variadic() && j == len(params)-1 {
+				params[j] = fmt.Sprintf("a%d ...%s", j, g.typeString(t.(*types.Slice).Elem()))
+			} else {
+				params[j] = fmt.Sprintf("a%d %s", j, g.typeString(t))
+			}
+		}
+		call := fmt.Sprintf("s.target.%s(%s)", method.Name(), strings.Join(args, ", "))
+		if sig.Variadic() {
+			call = call[:len(call)-1] + "...)"
+		}
+
+		resultTypes := make([]string, sig.Results().Len())
+		results := make([]string, len(resultTypes))
+		for j := range results {
+			resultTypes[j] = g.typeString(sig.Results().At(j).Type())
+			results[j] = fmt.Sprintf("r%d", j)
+		}
+
+		signature := "(" + strings.Join(params, ", ") + ")"
+		if len(resultTypes) > 0 {
+			signature += " (" + strings.Join(resultTypes, ", ") + ")"
+		}
+		fmt.Fprintf(decls, "func (s %s) %s%s {\n", name, method.Name(), signature)
+		recorded := "nil"
+		if len(results) > 0 {
+			fmt.Fprintf(decls, "%s := %s\n", strings.Join(results, ", "), call)
+			recorded = "[]any{" + strings.Join(results, ", ") + "}"
+		} else {
+			fmt.Fprintf(decls, "%s\n", call)
+		}
+		fmt.Fprintf(decls, "s.recorder.Record(%q, []any{%s}, %s)\n", method.Name(), strings.Join(args, ", "), recorded)
+		if len(results) > 0 {
+			fmt.Fprintf(decls, "return %s\n", strings.Join(results, ", "))
+		}
+		decls.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(body, "%s.RegisterSpy(func(target %s, recorder %s) %s {\nreturn %s{target, recorder}\n})\n",
+		g.importName(ditestPath, "ditest"), typeName, recorder, typeName, name)
+}
+
+// spyName the name of the proxy type of the interface (ex. spyRepository, spyIoWriter)
+func (g *generator) spyName(named *types.Named) string {
+	name := "spy" + named.Obj().Name()
+	if pkg := named.Obj().Pkg(); pkg != g.pkg.types {
+		name = "spy" + strings.ToUpper(pkg.Name()[:1]) + pkg.Name()[1:] + named.Obj().Name()
+	}
+
+	unique := name
+	for i := 2; g.names[unique] != "" || g.pkg.types.Scope().Lookup(unique) != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.names[unique] = unique
+	return unique
+}
+
+// writeWiring the wiring of the constructor: resolves the dependencies with typed calls
+// and calls the constructor
+func (g *generator) writeWiring(w *bytes.Buffer, wr *wiring) {
+	sig := wr.fn.Type().(*types.Signature)
+	ctor := g.ref(wr.fn)
+
+	fmt.Fprintf(w, "%s.RegisterWiring(%s, func(ctn %s.Container, ctx %s.Context) (any, error) {\n",
+		g.di(), ctor, g.di(), g.importName("context", "context"))
+
+	args := make([]string, sig.Params().Len())
+	for i := range args {
+		t := sig.Params().At(i).Type()
+		resolve := g.resolveCall(t)
+		switch resolve {
+		case "ctx", "ctn":
+			args[i] = resolve
+		default:
+			args[i] = fmt.Sprintf("a%d", i)
+			fmt.Fprintf(w, "a%d, err := %s\nif err != nil {\nreturn nil, err\n}\n", i, resolve)
+		}
+	}
+	call := ctor + "(" + strings.Join(args, ", ") + ")"
+
+	valueIdx, errorIdx, _ := resultShape(sig)
+	switch {
+	case valueIdx < 0 && errorIdx < 0:
+		fmt.Fprintf(w, "%s\nreturn nil, nil\n", call)
+	case valueIdx < 0:
+		// a nil concrete error (ex. *MyError) is not a nil error
+		fmt.Fprintf(w, "if err := %s; err != nil {\nreturn nil, err\n}\nreturn nil, nil\n", call)
+	case errorIdx < 0:
+		fmt.Fprintf(w, "return %s, nil\n", call)
+	default:
+		fmt.Fprintf(w, "%s := %s\nif err != nil {\nreturn nil, err\n}\nreturn v, nil\n", results(errorIdx), call)
+	}
+	w.WriteString("})\n")
+}
+
+// resolveCall the expression that resolves a dependency of the type in a wiring
+func (g *generator) resolveCall(t types.Type) string {
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil {
+		switch path, name := named.Obj().Pkg().Path(), named.Obj().Name(); {
+		case path == "context" && name == "Context":
+			return "ctx"
+		case path == diPath && name == "Container":
+			return "ctn"
+		case path == diPath && (name == "Provider" || name == "Unmanaged" || name == "Qualified"):
+			typeArgs := make([]string, named.TypeArgs().Len())
+			for i := range typeArgs {
+				typeArgs[i] = g.typeString(named.TypeArgs().At(i))
+			}
+			return fmt.Sprintf("%s.Resolve%s[%s](ctn, ctx)", g.di(), name, strings.Join(typeArgs, ", "))
+		}
+	}
+	return fmt.Sprintf("%s.Resolve[%s](ctn, ctx)", g.di(), g.typeString(t))
+}
+
+// writeProviders a function, never called, that assigns the result of the provider of
+// each dependency to the dependency type. A change in a provider that breaks the wiring,
+// without running di-gen again, is a compile error.
+func (g *generator) writeProviders(w *bytes.Buffer) {
+	var checks bytes.Buffer
+	seen := map[string]bool{}
+	check := func(t types.Type) {
+		t = dependencyOf(t)
+		if t == nil {
+			return
+		}
+		providers := g.providersOf(t)
+		if len(providers) != 1 || providers[0].fn == nil || !g.invokable(providers[0].fn) {
+			// resolved by the container (ex. instances, Primary)
+			return
+		}
+		fn := providers[0].fn
+		typeName := g.typeString(t)
+		key := typeName + " " + fn.FullName()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+
+		sig := fn.Type().(*types.Signature)
+		args := make([]string, sig.Params().Len())
+		for i := range args {
+			args[i] = "*new(" + g.typeString(sig.Params().At(i).Type()) + ")"
+		}
+		call := g.ref(fn) + "(" + strings.Join(args, ", ") + ")"
+		if valueIdx, errorIdx, _ := resultShape(sig); errorIdx < 0 {
+			fmt.Fprintf(&checks, "var _ %s = %s\n", typeName, call)
+		} else if valueIdx >= 0 {
+			fmt.Fprintf(&checks, "{\n%s := %s\nvar _ %s = v\n}\n", strings.Replace(results(errorIdx), "err", "_", 1), call, typeName)
+		}
+	}
+
+	for _, wr := range g.wirings {
+		params := wr.fn.Type().(*types.Signature).Params()
+		for i := 0; i < params.Len(); i++ {
+			check(params.At(i).Type())
+		}
+	}
+	for _, inj := range g.injectors {
+		for _, field := range injectFields(structOf(inj.typ)) {
+			check(field.Type())
+		}
+	}
+
+	if checks.Len() > 0 {
+		w.WriteString("\n// the providers of the dependencies, a change that breaks the wiring is a compile error\n")
+		w.WriteString("func _() {\n")
+		w.Write(checks.Bytes())
+		w.WriteString("}\n")
+	}
+}
+
+// results the names of the results of a constructor that returns a value and an error
+func results(errorIdx int) string {
+	if errorIdx == 0 {
+		return "err, v"
+	}
+	return "v, err"
+}
+
+func (g *generator) writeInjector(w *bytes.Buffer, inj *injector) {
+	typeName := g.typeString(inj.typ)
+
+	fmt.Fprintf(w, "%s.RegisterInjector(func(ctn %s.Container, ctx %s.Context) (out %s, err error) {\n",
+		g.di(), g.di(), g.importName("context", "context"), typeName)
+
+	if ptr, isPointer := inj.typ.(*types.Pointer); isPointer {
+		fmt.Fprintf(w, "out = &%s{}\n", g.typeString(ptr.Elem()))
+	}
+	for _, field := range injectFields(structOf(inj.typ)) {
+		fmt.Fprintf(w, "if out.%s, err = %s.Inject[%s, %s](ctn, ctx); err != nil {\nreturn\n}\n",
+			field.Name(), g.di(), g.typeString(field.Type()), typeName)
+	}
+	w.WriteString("return\n})\n")
+}
+
+// funcName the name of the function for messages
+func (g *generator) funcName(fn *types.Func) string {
+	if fn.Pkg() == g.pkg.types {
+		return fn.Name()
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
+
+// ref reference to the function in the generated code
+func (g *generator) ref(fn *types.Func) string {
+	if fn.Pkg() == g.pkg.types {
+		return fn.Name()
+	}
+	return g.importName(fn.Pkg().Path(), fn.Pkg().Name()) + "." + fn.Name()
+}
+
+func (g *generator) typeString(t types.Type) string {
+	return types.TypeString(t, func(p *types.Package) string {
+		if p == g.pkg.types {
+			return ""
+		}
+		return g.importName(p.Path(), p.Name())
+	})
+}
+
+// relative qualifier for messages
+func (g *generator) relative(p *types.Package) string {
+	if p == g.pkg.types {
+		return ""
+	}
+	return p.Name()
+}
+
+func (g *generator) di() string {
+	return g.importName(diPath, "di")
+}
+
+// importName the name of the imported package in the generated file
+func (g *generator) importName(path, name string) string {
+	if imported, exists := g.imports[path]; exists {
+		return imported
+	}
+
+	unique := name
+	for i := 2; g.names[unique] != "" || g.pkg.types.Scope().Lookup(unique) != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.imports[path] = unique
+	g.names[unique] = path
+	return unique
+}
+
+// pathName the default name of an import path (last element)
+func pathName(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// resultShape the index of the value and error results of a constructor (-1 if absent)
+func resultShape(sig *types.Signature) (valueIdx, errorIdx int, ok bool) {
+	results := sig.Results()
+	switch results.Len() {
+	case 0:
+		return -1, -1, true
+	case 1:
+		if isError(results.At(0).Type()) {
+			return -1, 0, true
+		}
+		return 0, -1, true
+	case 2:
+		if isError(results.At(1).Type()) {
+			return 0, 1, true
+		}
+		if isError(results.At(0).Type()) {
+			return 1, 0, true
+		}
+	}
+	return -1, -1, false
+}
+
+// isError same rule as the container, the type implements error
+func isError(t types.Type) bool {
+	return types.Implements(t, types.Universe.Lookup("error").Type().Underlying().(*types.Interface))
+}
+
+// structOf the struct of T or *T
+func structOf(t types.Type) *types.Struct {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if _, named := t.(*types.Named); !named {
+		return nil
+	}
+	st, _ := t.Underlying().(*types.Struct)
+	return st
+}
+
+// injectFields the exported fields with the `inject` tag (see di.InjectorOf)
+func injectFields(st *types.Struct) (fields []*types.Var) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Exported() {
+			continue
+		}
+		if _, hasTag := reflect.StructTag(st.Tag(i)).Lookup("inject"); hasTag {
+			fields = append(fields, field)
+		}
+	}
+	return
+}
+
+// dependencyOf the type resolved by the container for a parameter (nil if none)
+func dependencyOf(t types.Type) types.Type {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return t
+	}
+	switch path, name := named.Obj().Pkg().Path(), named.Obj().Name(); {
+	case path == "context" && name == "Context":
+		return nil
+	case path == diPath && name == "Container":
+		return nil
+	case path == diPath && (name == "Provider" || name == "Unmanaged" || name == "Qualified"):
+		return named.TypeArgs().At(0)
+	}
+	return t
+}
+
+func unparen(e ast.Expr) ast.Expr {
+	for {
+		paren, ok := e.(*ast.ParenExpr)
+		if !ok {
+			return e
+		}
+		e = paren.X
+	}
+}
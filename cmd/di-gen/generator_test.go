@@ -0,0 +1,152 @@
+package main
+
+import (
+	"go/parser"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	appOnce sync.Once
+	app     *pkg
+	appErr  error
+)
+
+// loadApp loads testdata/app once (type-checking from source is slow)
+func loadApp(t *testing.T) *pkg {
+	appOnce.Do(func() {
+		app, appErr = load(filepath.Join("testdata", "app"), "di_gen.go")
+	})
+	require.NoError(t, appErr)
+	return app
+}
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join("testdata", "app")
+
+	g := newGenerator(loadApp(t))
+	g.collect()
+	require.NoError(t, g.check())
+
+	src, err := g.generate()
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile(filepath.Join(dir, "di_gen.go"))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(src), "run: go run . -dir testdata/app")
+
+	// the package compiles with the generated code
+	_, err = load(dir, "")
+	require.NoError(t, err)
+}
+
+func TestGenerateSpies(t *testing.T) {
+	dir := filepath.Join("testdata", "app")
+	output := spiesOutput("di_gen.go")
+
+	g := newGenerator(loadApp(t))
+	g.collect()
+	src, err := g.generateSpies()
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile(filepath.Join(dir, output))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), string(src), "run: go run . -dir testdata/app -spies")
+
+	// the spies implement the interfaces
+	p, err := load(dir, "")
+	require.NoError(t, err)
+	file, err := parser.ParseFile(p.fset, filepath.Join(dir, output), nil, 0)
+	require.NoError(t, err)
+	p.files = append(p.files, file)
+	require.NoError(t, p.check(p.types.Path()))
+}
+
+func TestGenerateEmpty(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/missing\n\ngo 1.21\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app.go"), []byte(`package missing
+
+type Repository interface{ Find() }
+
+type Service struct{}
+
+func NewService(r Repository) *Service { return &Service{} }
+
+func init() {
+	var register func(any)
+	register(NewService)
+}
+`), 0o644))
+
+	p, err := load(dir, "di_gen.go")
+	require.NoError(t, err)
+
+	g := newGenerator(p)
+	g.collect()
+	require.Empty(t, g.wirings, "not registered with the di package")
+
+	src, err := g.generate()
+	require.NoError(t, err)
+	require.Equal(t, "// Code generated by di-gen. DO NOT EDIT.\n\npackage missing\n", string(src))
+}
+
+func TestCheckMissingProvider(t *testing.T) {
+	g := newGenerator(loadApp(t))
+	g.collect()
+	// without NewRepository
+	var provided []*provider
+	for _, p := range g.provided {
+		if !strings.HasSuffix(types.TypeString(p.typ, nil), "/app.repositoryImpl") {
+			provided = append(provided, p)
+		}
+	}
+	g.provided = provided
+
+	err := g.check()
+	require.ErrorContains(t, err, "NewService: no provider registered for Repository")
+	require.ErrorContains(t, err, "*Controller.Repository: no provider registered for Repository")
+}
+
+func TestCheckGraph(t *testing.T) {
+	// store.Config is provided by the application, which imports store
+	p, err := load(filepath.Join("testdata", "app", "store"), "di_gen.go")
+	require.NoError(t, err)
+	g := newGenerator(p)
+	g.collect()
+	require.ErrorContains(t, g.check(), "newIndex: no provider registered for Config")
+
+	g = newGenerator(loadApp(t))
+	g.collect()
+	require.NoError(t, g.check())
+	require.NotEmpty(t, loadApp(t).deps)
+}
+
+func TestGenerateProviders(t *testing.T) {
+	dir := filepath.Join("testdata", "app")
+	p, err := load(dir, "")
+	require.NoError(t, err)
+
+	// NewRepository no longer provides a Repository, without running di-gen again
+	src, err := os.ReadFile(filepath.Join(dir, "app.go"))
+	require.NoError(t, err)
+	changed := strings.Replace(string(src), "func NewRepository() *repositoryImpl {\n\treturn &repositoryImpl{}",
+		"func NewRepository() *Service {\n\treturn &Service{}", 1)
+	require.NotEqual(t, string(src), changed)
+
+	for i, file := range p.files {
+		if filepath.Base(p.fset.Position(file.Package).Filename) == "app.go" {
+			p.files[i], err = parser.ParseFile(p.fset, "app.go", changed, 0)
+			require.NoError(t, err)
+		}
+	}
+	err = p.check(p.types.Path())
+	require.ErrorContains(t, err, "di_gen.go")
+	require.ErrorContains(t, err, "does not implement Repository")
+}
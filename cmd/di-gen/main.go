@@ -0,0 +1,99 @@
+// Command di-gen generates the wiring code of a package: for each registered
+// constructor, a wiring that resolves its dependencies with typed calls and calls the
+// constructor directly. Creating the components no longer resolves the arguments nor
+// calls the constructors through reflection.
+//
+// di-gen reads the registrations (Register or ShouldRegister, di package or Container)
+// of the package and of the packages of the same module it imports, transitively: run
+// it in the package that imports all the others (ex. main). For each constructor the
+// generated code can call, it generates a wiring (see di.RegisterWiring), and for each
+// struct registered with Injector, Injected or InjectedTo, a typed injector (see
+// di.RegisterInjector). The generated code plugs into the container, the application
+// code does not change: the dependencies are resolved by the container (scopes,
+// qualifiers, mocks, ...), and components registered with function literals or
+// instances keep using reflection.
+//
+// Wiring errors are reported at generation and compile time:
+//
+//   - di-gen fails if a dependency has no provider registered in the graph
+//   - the generated code calls the constructors, a change in a constructor signature
+//     without running di-gen again is a compile error
+//   - the generated code assigns the result of the provider of each dependency (when
+//     there is a single one) to the dependency type, a provider that no longer
+//     provides the dependency is a compile error
+//
+// With -spies, di-gen also generates a test file with a proxy for each interface
+// provided or injected by the registrations of the graph, used by ditest.Spy to
+// record the calls to the real component.
+//
+// Usage:
+//
+//	//go:generate go run github.com/go-path/di/cmd/di-gen
+//
+// Flags:
+//
+//	-dir            package directory (default ".")
+//	-output         generated file name (default "di_gen.go")
+//	-allow-missing  do not fail if a dependency has no provider in the graph, the container reports it at runtime
+//	-spies          generate the spies of the interfaces ("<output>_spy_test.go", see ditest.Spy)
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "package directory")
+	output := flag.String("output", "di_gen.go", "generated file name")
+	allowMissing := flag.Bool("allow-missing", false, "do not fail if a dependency has no provider in the graph")
+	spies := flag.Bool("spies", false, "generate the spies of the interfaces (see ditest.Spy)")
+	flag.Parse()
+
+	if err := run(*dir, *output, *allowMissing, *spies); err != nil {
+		fmt.Fprintln(os.Stderr, "di-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string, allowMissing, spies bool) error {
+	pkg, err := load(dir, output)
+	if err != nil {
+		return err
+	}
+
+	g := newGenerator(pkg)
+	g.collect()
+
+	if !allowMissing {
+		if err := g.check(); err != nil {
+			return err
+		}
+	}
+
+	src, err := g.generate()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, output), src, 0o644); err != nil {
+		return err
+	}
+
+	if spies {
+		sg := newGenerator(pkg)
+		sg.collect()
+		if src, err = sg.generateSpies(); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, spiesOutput(output)), src, 0o644)
+	}
+	return nil
+}
+
+// spiesOutput the name of the spies file (ex. di_gen.go => di_gen_spy_test.go)
+func spiesOutput(output string) string {
+	return strings.TrimSuffix(output, ".go") + "_spy_test.go"
+}
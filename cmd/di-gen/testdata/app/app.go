@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/go-path/di"
+	"github.com/go-path/di/cmd/di-gen/testdata/app/store"
+)
+
+type Repository interface {
+	Find(id string) (string, error)
+}
+
+type repositoryImpl struct{}
+
+func (r *repositoryImpl) Find(id string) (string, error) {
+	return "", errors.New("not found")
+}
+
+func NewRepository() *repositoryImpl {
+	return &repositoryImpl{}
+}
+
+type Service struct {
+	repository Repository
+	client     *http.Client
+}
+
+func NewService(ctx context.Context, repository Repository, client di.Provider[*http.Client]) (*Service, error) {
+	c, err := client.Get()
+	if err != nil {
+		return nil, err
+	}
+	return &Service{repository: repository, client: c}, nil
+}
+
+type Controller struct {
+	Service    *Service   `inject:""`
+	Repository Repository `inject:""`
+	Name       string
+	hidden     *Service `inject:""`
+}
+
+type Job struct {
+	Service *Service  `inject:""`
+	Output  io.Writer `inject:""`
+}
+
+type Notifier interface {
+	io.Closer
+	Notify(ctx context.Context, to string, args ...any)
+	Pending() (int, error)
+}
+
+func NewNotifier(repository Repository) Notifier {
+	return nil
+}
+
+func StartJob(s *Service) error {
+	return nil
+}
+
+type MigrationError struct {
+	Version int
+}
+
+func (e *MigrationError) Error() string {
+	return "migration failed"
+}
+
+func Migrate(repository Repository) *MigrationError {
+	return nil
+}
+
+type Indexer struct {
+	cache store.Cache
+	index *store.Index
+}
+
+func NewIndexer(cache store.Cache, index *store.Index) *Indexer {
+	return &Indexer{cache: cache, index: index}
+}
+
+func NewStoreConfig() store.Config {
+	return store.Config{Path: "index"}
+}
+
+func newLocal() int {
+	type local struct{}
+	return 0
+}
+
+func init() {
+	di.Register(NewRepository)
+	di.Register(NewService, di.Prototype)
+	di.Register(&http.Client{})
+	di.Register(os.Stdout)
+	di.Register(NewNotifier)
+	di.Register(StartJob, di.Startup(10))
+	di.Register(Migrate, di.Startup(20))
+	di.Register(NewIndexer)
+	di.Register(NewStoreConfig)
+	di.Register(func(s *Service) string { return "literal" })
+	di.Injected[*Controller]()
+	di.InjectedTo[Job](di.Global())
+
+	ctn := di.New(nil)
+	ctn.Register(NewRepository) // duplicated
+	_ = newLocal
+}
@@ -0,0 +1,105 @@
+// Code generated by di-gen. DO NOT EDIT.
+
+package app
+
+import (
+	"context"
+	"github.com/go-path/di"
+	"github.com/go-path/di/cmd/di-gen/testdata/app/store"
+	"io"
+	"net/http"
+)
+
+func init() {
+	di.RegisterWiring(NewRepository, func(ctn di.Container, ctx context.Context) (any, error) {
+		return NewRepository(), nil
+	})
+	di.RegisterWiring(NewService, func(ctn di.Container, ctx context.Context) (any, error) {
+		a1, err := di.Resolve[Repository](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		a2, err := di.ResolveProvider[*http.Client](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		v, err := NewService(ctx, a1, a2)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+	di.RegisterWiring(NewNotifier, func(ctn di.Container, ctx context.Context) (any, error) {
+		a0, err := di.Resolve[Repository](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return NewNotifier(a0), nil
+	})
+	di.RegisterWiring(StartJob, func(ctn di.Container, ctx context.Context) (any, error) {
+		a0, err := di.Resolve[*Service](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := StartJob(a0); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	di.RegisterWiring(Migrate, func(ctn di.Container, ctx context.Context) (any, error) {
+		a0, err := di.Resolve[Repository](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := Migrate(a0); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	})
+	di.RegisterWiring(NewIndexer, func(ctn di.Container, ctx context.Context) (any, error) {
+		a0, err := di.Resolve[store.Cache](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		a1, err := di.Resolve[*store.Index](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return NewIndexer(a0, a1), nil
+	})
+	di.RegisterWiring(NewStoreConfig, func(ctn di.Container, ctx context.Context) (any, error) {
+		return NewStoreConfig(), nil
+	})
+	di.RegisterWiring(store.NewCache, func(ctn di.Container, ctx context.Context) (any, error) {
+		return store.NewCache(), nil
+	})
+	di.RegisterInjector(func(ctn di.Container, ctx context.Context) (out *Controller, err error) {
+		out = &Controller{}
+		if out.Service, err = di.Inject[*Service, *Controller](ctn, ctx); err != nil {
+			return
+		}
+		if out.Repository, err = di.Inject[Repository, *Controller](ctn, ctx); err != nil {
+			return
+		}
+		return
+	})
+	di.RegisterInjector(func(ctn di.Container, ctx context.Context) (out Job, err error) {
+		if out.Service, err = di.Inject[*Service, Job](ctn, ctx); err != nil {
+			return
+		}
+		if out.Output, err = di.Inject[io.Writer, Job](ctn, ctx); err != nil {
+			return
+		}
+		return
+	})
+}
+
+// the providers of the dependencies, a change that breaks the wiring is a compile error
+func _() {
+	var _ Repository = NewRepository()
+	{
+		v, _ := NewService(*new(context.Context), *new(Repository), *new(di.Provider[*http.Client]))
+		var _ *Service = v
+	}
+	var _ store.Cache = store.NewCache()
+}
@@ -0,0 +1,80 @@
+// Code generated by di-gen. DO NOT EDIT.
+
+package app
+
+import (
+	"context"
+	"github.com/go-path/di/cmd/di-gen/testdata/app/store"
+	"github.com/go-path/di/ditest"
+	"io"
+)
+
+type spyNotifier struct {
+	target   Notifier
+	recorder *ditest.Recorder
+}
+
+func (s spyNotifier) Close() error {
+	r0 := s.target.Close()
+	s.recorder.Record("Close", []any{}, []any{r0})
+	return r0
+}
+
+func (s spyNotifier) Notify(a0 context.Context, a1 string, a2 ...any) {
+	s.target.Notify(a0, a1, a2...)
+	s.recorder.Record("Notify", []any{a0, a1, a2}, nil)
+}
+
+func (s spyNotifier) Pending() (int, error) {
+	r0, r1 := s.target.Pending()
+	s.recorder.Record("Pending", []any{}, []any{r0, r1})
+	return r0, r1
+}
+
+type spyRepository struct {
+	target   Repository
+	recorder *ditest.Recorder
+}
+
+func (s spyRepository) Find(a0 string) (string, error) {
+	r0, r1 := s.target.Find(a0)
+	s.recorder.Record("Find", []any{a0}, []any{r0, r1})
+	return r0, r1
+}
+
+type spyStoreCache struct {
+	target   store.Cache
+	recorder *ditest.Recorder
+}
+
+func (s spyStoreCache) Get(a0 string) (string, bool) {
+	r0, r1 := s.target.Get(a0)
+	s.recorder.Record("Get", []any{a0}, []any{r0, r1})
+	return r0, r1
+}
+
+type spyIoWriter struct {
+	target   io.Writer
+	recorder *ditest.Recorder
+}
+
+func (s spyIoWriter) Write(a0 []byte) (int, error) {
+	r0, r1 := s.target.Write(a0)
+	s.recorder.Record("Write", []any{a0}, []any{r0, r1})
+	return r0, r1
+}
+
+func init() {
+	ditest.RegisterSpy(func(target Notifier, recorder *ditest.Recorder) Notifier {
+		return spyNotifier{target, recorder}
+	})
+	ditest.RegisterSpy(func(target Repository, recorder *ditest.Recorder) Repository {
+		return spyRepository{target, recorder}
+	})
+	ditest.RegisterSpy(func(target store.Cache, recorder *ditest.Recorder) store.Cache {
+		return spyStoreCache{target, recorder}
+	})
+	ditest.RegisterSpy(func(target io.Writer, recorder *ditest.Recorder) io.Writer {
+		return spyIoWriter{target, recorder}
+	})
+}
@@ -0,0 +1,35 @@
+package store
+
+import "github.com/go-path/di"
+
+type Cache interface {
+	Get(key string) (string, bool)
+}
+
+type memoryCache struct{}
+
+func (c *memoryCache) Get(key string) (string, bool) {
+	return "", false
+}
+
+func NewCache() *memoryCache {
+	return &memoryCache{}
+}
+
+// Config provided by the application
+type Config struct {
+	Path string
+}
+
+type Index struct {
+	path string
+}
+
+func newIndex(config Config) *Index {
+	return &Index{path: config.Path}
+}
+
+func init() {
+	di.Register(NewCache)
+	di.Register(newIndex)
+}
@@ -0,0 +1,156 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testGoroutines = 64
+
+// runConcurrently runs fn in testGoroutines goroutines, released at the same time
+func runConcurrently(t *testing.T, fn func(i int) error) {
+	t.Helper()
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	errs := make(chan error, testGoroutines)
+	for i := 0; i < testGoroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs <- fn(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
+
+func TestConcurrentSingleton(t *testing.T) {
+	var leafCount, rootCount atomic.Int32
+
+	ctn := New(nil)
+	ctn.Register(func() *testResolveLeaf {
+		leafCount.Add(1)
+		time.Sleep(5 * time.Millisecond)
+		return &testResolveLeaf{}
+	})
+	ctn.Register(func(l *testResolveLeaf) *testResolveMiddle { return &testResolveMiddle{} }, Prototype)
+	ctn.Register(func(m *testResolveMiddle) *testResolveRoot {
+		rootCount.Add(1)
+		return &testResolveRoot{}
+	})
+	require.NoError(t, ctn.Initialize())
+
+	roots := make([]*testResolveRoot, testGoroutines)
+	runConcurrently(t, func(i int) (err error) {
+		if i%2 == 0 {
+			_, err = GetFrom[*testResolveMiddle](ctn)
+		}
+		if err == nil {
+			roots[i], err = GetFrom[*testResolveRoot](ctn)
+		}
+		return
+	})
+
+	require.Equal(t, int32(1), leafCount.Load())
+	require.Equal(t, int32(1), rootCount.Load())
+	for _, root := range roots {
+		require.Same(t, roots[0], root)
+	}
+}
+
+func TestConcurrentSingletonError(t *testing.T) {
+	var count atomic.Int32
+	ctn := New(nil)
+	ctn.Register(func() (*testResolveLeaf, error) {
+		count.Add(1)
+		time.Sleep(5 * time.Millisecond)
+		return nil, context.Canceled
+	})
+	require.NoError(t, ctn.Initialize())
+
+	var failed atomic.Int32
+	runConcurrently(t, func(i int) error {
+		if _, err := GetFrom[*testResolveLeaf](ctn); err != nil {
+			failed.Add(1)
+		}
+		return nil
+	})
+	require.Equal(t, int32(testGoroutines), failed.Load())
+	require.Less(t, count.Load(), int32(testGoroutines))
+}
+
+func TestConcurrentRegistrationAndInjectors(t *testing.T) {
+	types := []reflect.Type{
+		reflect.TypeOf(&struct{ A int }{}),
+		reflect.TypeOf(&struct{ B int }{}),
+		reflect.TypeOf(&struct{ C int }{}),
+	}
+
+	// containers registering in parallel
+	var ids sync.Map
+	runConcurrently(t, func(i int) error {
+		InjectorOf(types[i%len(types)])
+
+		ctn := New(nil)
+		if err := ctn.ShouldRegister(func() int { return i }); err != nil {
+			return err
+		}
+		for _, f := range ctn.(*container).factories[reflect.TypeOf(0)] {
+			if _, duplicated := ids.LoadOrStore(f.Id(), true); duplicated {
+				return fmt.Errorf("duplicated factory id %d", f.Id())
+			}
+		}
+		return nil
+	})
+}
+
+func TestConcurrentMock(t *testing.T) {
+	ctn := New(nil)
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} }, Prototype)
+	require.NoError(t, ctn.Initialize())
+
+	runConcurrently(t, func(i int) error {
+		if i%4 == 0 {
+			cleanup := ctn.Mock(testResolveLeaf{})
+			defer cleanup()
+		}
+		_, err := ctn.Get(Key[*testResolveLeaf]())
+		return err
+	})
+}
+
+func TestSingletonDisposerResolves(t *testing.T) {
+	ctn := New(nil)
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ctn.Register(func() *testResolveRoot { return &testResolveRoot{} }, Disposer(func(*testResolveRoot) {
+		_, err := GetFrom[*testResolveLeaf](ctn)
+		require.NoError(t, err)
+	}))
+	require.NoError(t, ctn.Initialize())
+	MustGetFrom[*testResolveRoot](ctn)
+
+	done := make(chan error)
+	go func() {
+		done <- ctn.Destroy()
+	}()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("deadlock: the disposer resolves a component")
+	}
+}
@@ -5,20 +5,29 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 type ctxCurrentInCreationKeyType int // unexported type for ctxCurrentInCreationKey to avoid collisions.
 
+type ctxDelegatedKeyType int // unexported type for ctxDelegatedKey to avoid collisions.
+
 type Container interface {
 
 	// Initialize initialize all non-lazy singletons (startup)
 	Initialize(ctx ...context.Context) error
 
+	// Configure reconfigure this container. Must be called before Initialize
+	Configure(configs ...ContainerConfig) error
+
 	Register(ctor any, opts ...FactoryConfig)
 
 	ShouldRegister(ctor any, opts ...FactoryConfig) error
@@ -37,6 +46,13 @@ type Container interface {
 
 	Filter(options ...FactoryConfig) *FilteredFactories
 
+	// Graph returns a snapshot of the dependency graph (factories and their dependencies)
+	Graph() *DependencyGraph
+
+	// Validate checks all factories for missing or ambiguous dependencies, unregistered
+	// scopes and scope violations, without instantiating any component.
+	Validate() error
+
 	GetObjectFactory(factory *Factory, managed bool, ctx ...context.Context) CreateObjectFunc
 
 	GetObjectFactoryFor(key reflect.Type, managed bool, ctx ...context.Context) CreateObjectFunc
@@ -55,27 +71,72 @@ type Container interface {
 
 	// Mock test only, register a mock instance to the container
 	Mock(mock any) (cleanup func())
+
+	// Clone creates a container with the registrations and settings of this container,
+	// without its instances. The clone is not initialized and has the same parent.
+	Clone() Container
+
+	// NewChild creates a child container with the settings and scopes of this container.
+	// See container.NewChild
+	NewChild(configs ...ContainerConfig) Container
+
+	// Publish dispatches the event to the listeners of this container (see Listener)
+	Publish(ctx context.Context, event any) error
+
+	// Workers returns the status of the workers started by Initialize (see Worker)
+	Workers() []WorkerStatus
 }
 
 type container struct {
-	locked         bool // by design, we lock the container after initialization
-	graph          *graph
-	parent         Container
-	paramsMu       sync.RWMutex
-	mockMu         sync.Mutex
-	scopes         map[string]ScopeI
-	knownParams    map[reflect.Type]*Parameter
-	factories      map[reflect.Type][]*Factory
-	singletons     *scopeSingleton
-	testingHasMock bool
-	testingMocks   map[reflect.Type]mockFunc
+	locked          bool // by design, we lock the container after initialization
+	graph           *graph
+	parent          Container
+	paramsMu        sync.RWMutex
+	mockMu          sync.RWMutex
+	scopes          map[string]ScopeI
+	sharedScopes    map[string]bool       // scopes of the parent, not destroyed by this container (see WithParentFactories)
+	child           bool                  // created by NewChild
+	fakes           []*Factory            // see FakeFor
+	fakeQualifiers  map[reflect.Type]bool // components replaced by fakes (see WithFakes)
+	knownParams     map[reflect.Type]*Parameter
+	factories       map[reflect.Type][]*Factory
+	singletons      *scopeSingleton
+	testingHasMock  bool
+	testingMocks    map[reflect.Type]mockFunc
+	logger          *slog.Logger
+	logLevels       map[LogEvent]slog.Level
+	strict          bool   // only exact matches are injected (no candidates)
+	defaultScope    string // scope of the components without an explicit scope
+	lazyDefault     bool   // lazy initialization of singletons without Lazy or Eager config
+	maxDepth        int    // max resolution depth (0 = unlimited)
+	mockAllowed     bool   // allow Mock (default = testing.Testing())
+	tracer          Tracer
+	startupReport   func(*StartupReport)
+	metrics         Metrics
+	initParallel    bool // initialize independent components concurrently
+	initWorkers     int  // max concurrent initializations
+	plansMu         sync.RWMutex
+	plansGeneration int                          // incremented when the plans are invalidated
+	keyPlans        map[reflect.Type]*Factory    // factory selected for each key (see getKeyPlan)
+	factoryPlans    map[*Factory]*resolutionPlan // resolution plan of each factory (see getFactoryPlan)
+	listenerPlans   map[reflect.Type][]*Factory  // listeners of each event type (see listenersOf)
+	asyncMu         sync.Mutex
+	asyncWg         sync.WaitGroup // async listeners running (see Async)
+	asyncStopped    bool           // shutdown started, async listeners are not notified
+	workersMu       sync.Mutex
+	workers         []*worker // see Worker
+	workersCancel   context.CancelFunc
+	workersWg       sync.WaitGroup
+	shutdownTimeout time.Duration // see WithShutdownTimeout
+	clock           Clock         // see WithClock
 }
 
 var (
-	fseq                    = 0
+	fseq                    atomic.Int64
 	ctxCurrentInCreationKey ctxCurrentInCreationKeyType
+	ctxDelegatedKey         ctxDelegatedKeyType // Get delegated by a child container, see Get
 	initializersStereotype  = Stereotype(Singleton, Condition(func(c Container, f *Factory) bool {
-		return f.Startup()
+		return f.Startup() || !f.Lazy()
 	}))
 	ErrCycleDetected         = errors.New("this component introduces a cycle")
 	ErrManyCandidates        = errors.New("multiple candidates found")
@@ -87,28 +148,68 @@ var (
 	ErrNoScopeNameDefined    = errors.New("no scope name defined for component")
 	ErrCurrentlyInCreation   = errors.New("requested component is currently in creation")
 	ErrNoScopeNameRegistered = errors.New("no Scope registered")
+	ErrScopeViolation        = errors.New("scope violation")
+	ErrMaxDepthExceeded      = errors.New("max resolution depth exceeded")
+	ErrNoFake                = errors.New("no fake registered")
+	ErrNotVisible            = errors.New("component is not visible")
+	ErrShutdownTimeout       = errors.New("did not stop before the shutdown timeout")
+	ErrInvalidSchedule       = errors.New("invalid schedule")
 )
 
-func New(parent Container) Container {
+// New creates a new container. The parent container is used to resolve the
+// components not found in this container.
+//
+// Example:
+//
+//	ctn := di.New(nil, di.WithLogger(logger))
+func New(parent Container, configs ...ContainerConfig) Container {
 	c := &container{
-		graph:          &graph{},
-		parent:         parent,
-		scopes:         make(map[string]ScopeI),
-		factories:      make(map[reflect.Type][]*Factory),
-		singletons:     newSingletonScope(),
-		testingHasMock: false,
-		testingMocks:   make(map[reflect.Type]mockFunc),
-		knownParams:    make(map[reflect.Type]*Parameter),
+		graph:           &graph{},
+		parent:          parent,
+		scopes:          make(map[string]ScopeI),
+		sharedScopes:    make(map[string]bool),
+		factories:       make(map[reflect.Type][]*Factory),
+		singletons:      newSingletonScope(),
+		testingHasMock:  false,
+		testingMocks:    make(map[reflect.Type]mockFunc),
+		knownParams:     make(map[reflect.Type]*Parameter),
+		logLevels:       maps.Clone(defaultLogLevels),
+		defaultScope:    SCOPE_SINGLETON,
+		lazyDefault:     true,
+		mockAllowed:     testing.Testing(),
+		keyPlans:        make(map[reflect.Type]*Factory),
+		factoryPlans:    make(map[*Factory]*resolutionPlan),
+		listenerPlans:   make(map[reflect.Type][]*Factory),
+		shutdownTimeout: 30 * time.Second,
+		clock:           realClock{},
+	}
+
+	for _, config := range configs {
+		config(c)
 	}
 
 	c.scopes[SCOPE_SINGLETON] = c.singletons
 	c.scopes[SCOPE_PROTOTYPE] = &scopePrototypeImpl{}
+	if _, exists := c.scopes[SCOPE_JOB]; !exists {
+		c.scopes[SCOPE_JOB] = &scopeJob{}
+	}
 
 	c.graph.container = c
 	return c
 }
 
-func (c *container) Initialize(contexts ...context.Context) error {
+func (c *container) Initialize(contexts ...context.Context) (err error) {
+	if len(c.fakeQualifiers) > 0 && !c.locked {
+		if err = c.applyFakes(); err != nil {
+			return err
+		}
+	}
+	if c.child && !c.locked {
+		if err = c.rebindParentFactories(); err != nil {
+			return err
+		}
+	}
+
 	c.paramsMu.Lock()
 	if c.locked {
 		c.paramsMu.Unlock()
@@ -123,15 +224,79 @@ func (c *container) Initialize(contexts ...context.Context) error {
 
 	c.paramsMu.Unlock()
 
-	// @TODO: Fazer log de todos os Factories registrados
+	// precompute the resolution of all known components
+	c.compilePlans()
+
+	if c.startupReport != nil {
+		profiler := newStartupProfiler()
+		ctx = context.WithValue(ctx, ctxStartupProfilerKey, profiler)
+		defer func() {
+			c.startupReport(profiler.report(err))
+		}()
+	}
+
+	initializers := c.Filter(initializersStereotype)
+	if c.initParallel {
+		err = c.initializeParallel(ctx, initializers.factories)
+	} else {
+		err = initializers.Foreach(func(f *Factory) (bool, error) {
+			if _, _, err := c.GetObjectFactory(f, true, ctx)(); err != nil {
+				return true, err
+			}
+
+			return false, nil
+		})
+	}
+	if err == nil {
+		err = c.Publish(ctx, ContainerInitialized{Container: c})
+	}
+	if err == nil {
+		err = c.startWorkers(getContext(contexts...))
+	}
+	if err != nil {
+		c.log(LogError, "[di] initialization failed", slog.Any("error", err))
+	}
+	return err
+}
+
+// scopeOf the scope of a component registered without an explicit scope. Startup and
+// Eager components are created by Initialize, so they are always singletons.
+func (c *container) scopeOf(f *Factory) string {
+	if f.startup || (f.lazySet && !f.lazy) {
+		return SCOPE_SINGLETON
+	}
+	return c.defaultScope
+}
+
+func (c *container) Configure(configs ...ContainerConfig) error {
+	if c.locked {
+		return ErrContainerLocked
+	}
 
-	return c.Filter(initializersStereotype).Foreach(func(f *Factory) (bool, error) {
-		if _, _, err := c.GetObjectFactory(f, true, ctx)(); err != nil {
-			return true, err
+	for _, config := range configs {
+		config(c)
+	}
+
+	// update components (and fakes) registered with default settings
+	for _, factories := range c.factories {
+		for _, f := range factories {
+			c.applyDefaults(f)
 		}
+	}
+	for _, f := range c.fakes {
+		c.applyDefaults(f)
+	}
+	return nil
+}
 
-		return false, nil
-	})
+// applyDefaults sets the default scope and lazy settings, if not configured explicitly
+func (c *container) applyDefaults(f *Factory) {
+	if !f.scopeSet {
+		f.scope = c.scopeOf(f)
+	}
+	if !f.lazySet {
+		f.lazy = c.lazyDefault
+	}
 }
 
 func (c *container) RegisterScope(name string, scope ScopeI) error {
@@ -253,9 +418,8 @@ func (c *container) ShouldRegister(funcOrRef any, options ...FactoryConfig) erro
 		}
 	}
 
-	fseq++
 	factory := &Factory{
-		id:             fseq,
+		id:             int(fseq.Add(1)),
 		key:            returnKey,
 		name:           returnKey.String() + "_" + factoryValue.String(),
 		factoryType:    factoryType,
@@ -265,16 +429,27 @@ func (c *container) ShouldRegister(funcOrRef any, options ...FactoryConfig) erro
 		returnValueIdx: returnValueIdx,
 		parameterKeys:  paramsKeys,
 		isReference:    isSingletonInstance,
-		scope:          SCOPE_SINGLETON,
+		scope:          "",
 		qualifiers:     make(map[reflect.Type]bool),
 	}
+	if injector, ok := funcOrRef.(interface{ injectKeys() []reflect.Type }); ok {
+		factory.injectKeys = injector.injectKeys()
+	}
 
 	for _, option := range options {
 		option(factory)
 	}
 
+	factory.scopeSet = factory.scope != ""
 	if isSingletonInstance {
 		factory.scope = SCOPE_SINGLETON
+		factory.scopeSet = true
+	} else if !factory.scopeSet {
+		factory.scope = c.scopeOf(factory)
+	}
+
+	if !factory.lazySet {
+		factory.lazy = c.lazyDefault
 	}
 
 	// ignore if the factory returns nil
@@ -290,6 +465,24 @@ func (c *container) ShouldRegister(funcOrRef any, options ...FactoryConfig) erro
 		}
 	}
 
+	if factory.schedule != nil {
+		if err := checkScheduled(factory); err != nil {
+			return err
+		}
+	}
+
+	if factory.fake != nil {
+		// registered on Initialize (see WithFakes)
+		return c.addFake(factory)
+	}
+
+	return c.addFactory(factory)
+}
+
+// addFactory adds the factory to the dependency graph
+func (c *container) addFactory(factory *Factory) error {
+	returnKey := factory.key
+
 	// cache old providers before running cycle detection.
 	oldFactories := c.factories[returnKey]
 	c.factories[returnKey] = append(c.factories[returnKey], factory)
@@ -300,16 +493,189 @@ func (c *container) ShouldRegister(funcOrRef any, options ...FactoryConfig) erro
 		// the providers map back to what it was before this node was
 		// introduced.
 		c.factories[returnKey] = oldFactories
-		fmt.Println(cycle)
-		return ErrCycleDetected
+		c.graph.nodes = c.graph.nodes[:factory.g]
+
+		path := make([]*Factory, len(cycle))
+		for i, u := range cycle {
+			if u == factory.g {
+				path[i] = factory
+			} else {
+				path[i] = c.graph.nodes[u]
+			}
+		}
+		return &CycleError{Path: path, Err: ErrCycleDetected}
 	}
 
 	// update cache
 	c.GetParam(returnKey)
-	for _, paramKey := range paramsKeys {
+	for _, paramKey := range factory.parameterKeys {
 		factory.parameters = append(factory.parameters, c.GetParam(paramKey))
 	}
 
+	if c.logEnabled(LogRegistration) {
+		c.log(LogRegistration, "[di] component registered",
+			slog.String("key", factory.String()),
+			slog.String("scope", factory.scope),
+			slog.String("source", factory.Source()),
+		)
+	}
+
+	return nil
+}
+
+func (c *container) Clone() Container {
+	clone := New(c.parent).(*container)
+	clone.copySettings(c)
+	clone.child = c.child
+	clone.copyFactories(c)
+	return clone
+}
+
+// NewChild creates a child container with the settings (logger, default scope, mocks...)
+// and the custom scopes of this container. Components can be registered in the child
+// even after this container is initialized.
+//
+// The child resolves the components it does not register in this container, sharing its
+// singletons. Components of this container that depend (directly or not) on components
+// registered in the child are created by the child on its own instances, which lets each
+// child override a few bindings. Destroy only disposes the instances of the child.
+//
+// Example:
+//
+//	tenant := ctn.NewChild()
+//	tenant.Register(func() *TenantDB { return openTenantDB(id) })
+//	tenant.Initialize() // *Repository (depends on *TenantDB) is created by the tenant
+func (c *container) NewChild(configs ...ContainerConfig) Container {
+	child := New(c).(*container)
+	child.copySettings(c)
+	child.copyScopes(c)
+	child.child = true
+	for _, config := range configs {
+		config(child)
+	}
+	return child
+}
+
+// copySettings copies the configuration of the given container
+func (c *container) copySettings(from *container) {
+	c.logger = from.logger
+	c.logLevels = maps.Clone(from.logLevels)
+	c.strict = from.strict
+	c.defaultScope = from.defaultScope
+	c.lazyDefault = from.lazyDefault
+	c.maxDepth = from.maxDepth
+	c.mockAllowed = from.mockAllowed
+	c.tracer = from.tracer
+	c.startupReport = from.startupReport
+	c.metrics = from.metrics
+	c.initParallel = from.initParallel
+	c.initWorkers = from.initWorkers
+	c.shutdownTimeout = from.shutdownTimeout
+	c.clock = from.clock
+	c.fakeQualifiers = maps.Clone(from.fakeQualifiers)
+}
+
+// copyScopes shares the custom scopes of the given container, they are not destroyed by
+// this container
+func (c *container) copyScopes(from *container) {
+	for name, scope := range from.scopes {
+		if _, exists := c.scopes[name]; !exists {
+			c.scopes[name] = scope
+			c.sharedScopes[name] = true
+		}
+	}
+}
+
+// copyFactories registers the factories (and scopes) of the given container, without
+// their instances (see Clone and WithParentFactories)
+func (c *container) copyFactories(from *container) {
+	if from.graph == nil {
+		// destroyed
+		return
+	}
+
+	c.copyScopes(from)
+
+	// registration order. Conditions were checked and the graph is acyclic.
+	for _, f := range from.graph.nodes {
+		if !from.registered(f) {
+			// replaced by a fake
+			continue
+		}
+		factory := *f
+		factory.id = int(fseq.Add(1))
+		factory.parameters = nil
+		factory.qualifiers = maps.Clone(f.qualifiers)
+		factory.g = c.graph.add(&factory)
+		c.factories[factory.key] = append(c.factories[factory.key], &factory)
+
+		c.GetParam(factory.key)
+		for _, paramKey := range factory.parameterKeys {
+			factory.parameters = append(factory.parameters, c.GetParam(paramKey))
+		}
+	}
+
+	for _, f := range from.fakes {
+		fake := *f
+		fake.id = int(fseq.Add(1))
+		fake.qualifiers = maps.Clone(f.qualifiers)
+		c.fakes = append(c.fakes, &fake)
+	}
+}
+
+// rebindParentFactories registers the components of the parent that depend (directly
+// or not, by parameter or `inject` field) on components registered in this child, in
+// the registration order. The child creates them with its own dependencies (see NewChild).
+func (c *container) rebindParentFactories() error {
+	parent, ok := c.parent.(*container)
+	if !ok || parent.graph == nil {
+		return nil
+	}
+
+	overridden := func(p *Parameter) bool {
+		for _, factories := range c.factories {
+			for _, f := range factories {
+				if isCandidate, isExactMatch := p.IsValidCandidate(f); isCandidate && (isExactMatch || !c.strict) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// components registered in the child replace the ones of the parent
+	own := map[reflect.Type]bool{}
+	for key := range c.factories {
+		own[key] = true
+	}
+
+	rebound := map[*Factory]bool{}
+	for changed := true; changed; {
+		changed = false
+		for _, f := range parent.graph.nodes {
+			if rebound[f] || own[f.key] || !parent.registered(f) {
+				continue
+			}
+			params := f.parameters
+			for _, key := range f.injectKeys {
+				params = append(params[:len(params):len(params)], c.GetParam(key))
+			}
+			for _, p := range params {
+				if !overridden(p) {
+					continue
+				}
+				factory := *f
+				factory.id = int(fseq.Add(1))
+				factory.parameters = nil
+				if err := c.addFactory(&factory); err != nil {
+					return err
+				}
+				rebound[f] = true
+				changed = true
+				break
+			}
+		}
+	}
 	return nil
 }
 
@@ -474,9 +840,9 @@ func (c *container) refreshAliasFn(loop func(func(*Parameter))) {
 
 				if isExactMatch {
 					p.factories[f] = true
-				} else {
+				} else if !c.strict {
 					p.candidates[f] = true
-					slog.Info(fmt.Sprintf("[di] '%s' is a candidate for '%s'", returnType.String(), paramType.String()))
+					c.log(LogCandidate, fmt.Sprintf("[di] '%s' is a candidate for '%s'", returnType.String(), paramType.String()))
 				}
 			}
 		})
@@ -487,6 +853,16 @@ func (c *container) refreshAliasFn(loop func(func(*Parameter))) {
 func (c *container) Get(key reflect.Type, contexts ...context.Context) (instance any, e error) {
 	ctx := getContext(contexts...)
 
+	defer func() {
+		// only the container that received the request reports the error
+		if e != nil && getCreationPath(ctx) == nil && ctx.Value(ctxDelegatedKey) == nil {
+			c.log(LogError, "[di] cannot resolve component", slog.String("key", key.String()), slog.Any("error", e))
+			if c.metrics != nil {
+				c.metrics.ResolutionFailed(key, resolutionErrorKind(e))
+			}
+		}
+	}()
+
 	if key == _keyContext {
 		// ignore context.Context
 		return ctx, nil
@@ -497,20 +873,37 @@ func (c *container) Get(key reflect.Type, contexts ...context.Context) (instance
 		return c, nil
 	}
 
-	param := c.GetParam(key)
+	factory := c.getKeyPlan(key)
+	if factory == nil {
+		param := c.GetParam(key)
 
-	// Check if component exists in this container
-	if c.parent != nil && !c.Contains(key) {
-		// not found -> check parent.
-		return c.parent.Get(key, ctx)
+		// Check if component exists in this container
+		if c.parent != nil && !c.Contains(key) {
+			// not found -> check parent.
+			return c.parent.Get(key, context.WithValue(ctx, ctxDelegatedKey, true))
+		}
+
+		factory, e = c.resolveFactory(param, ctx)
+		if e != nil {
+			return
+		}
 	}
 
-	var factory *Factory
-	factory, e = c.resolveFactory(param)
-	if e != nil {
+	if e = checkVisibility(key, factory, ctx); e != nil {
 		return
 	}
 
+	// singletons receive a proxy for scoped components (see ScopedProxy)
+	if factory.proxyFor(key) {
+		if path := getCreationPath(ctx); path != nil && path.factory.Singleton() {
+			instance = factory.proxy(func(ctx context.Context) (any, error) {
+				obj, _, err := c.createObject(factory.key, factory, ctx, true)
+				return obj, err
+			})
+			return
+		}
+	}
+
 	fid := factory.Id()
 
 	// eagerly check singleton cache for manually registered singletons.
@@ -520,19 +913,19 @@ func (c *container) Get(key reflect.Type, contexts ...context.Context) (instance
 	}
 
 	// fail if we're already creating this instance: we're assumably within a circular reference.
-	if c.isInCreation(fid, ctx) {
-		e = errors.Join(fmt.Errorf(`circular reference for "%s"`, key.String()), ErrCurrentlyInCreation)
+	if path := getCreationPath(ctx); path.contains(fid) {
+		e = &CycleError{Path: append(path.factories(), factory), Err: ErrCurrentlyInCreation}
 		return
 	}
 
-	instance, _, e = c.createObject(factory, ctx, true)
+	instance, _, e = c.createObject(key, factory, ctx, true)
 	return
 }
 
 // ObjectFactory get a factory for a managed component (by scope)
 func (c *container) GetObjectFactory(factory *Factory, managed bool, ctx ...context.Context) CreateObjectFunc {
 	return func() (any, DisposableAdapter, error) {
-		return c.createObject(factory, getContext(ctx...), managed)
+		return c.createObject(factory.key, factory, getContext(ctx...), managed)
 	}
 }
 
@@ -544,38 +937,70 @@ func (c *container) GetObjectFactoryFor(key reflect.Type, managed bool, ctx ...c
 		return c.parent.GetObjectFactoryFor(key, managed, ctx...)
 	}
 
-	param := c.GetParam(key)
-	var factory *Factory
-	factory, e := c.resolveFactory(param)
+	factory, e := c.factoryFor(key, getContext(ctx...))
 
 	return func() (any, DisposableAdapter, error) {
 		if e != nil {
 			return nil, nil, e
 		}
 
-		return c.createObject(factory, getContext(ctx...), managed)
+		return c.createObject(key, factory, getContext(ctx...), managed)
 	}
 }
 
-func (c *container) createObject(factory *Factory, ctx context.Context, managed bool) (instance any, disposer DisposableAdapter, e error) {
+// factoryFor the factory selected to create the component key (the mocks included)
+func (c *container) factoryFor(key reflect.Type, ctx context.Context) (*Factory, error) {
+	if factory := c.getKeyPlan(key); factory != nil {
+		return factory, nil
+	}
+	return c.resolveFactory(c.GetParam(key), ctx)
+}
+
+func (c *container) createObject(key reflect.Type, factory *Factory, ctx context.Context, managed bool) (instance any, disposer DisposableAdapter, e error) {
 	if factory.mock != nil {
-		instance = factory.mock(ctx)
+		instance, e = factory.mock(ctx)
 		return
 	}
 
-	if err := c.checkMissingDependencies(factory); err != nil {
-		e = errors.Join(ErrMissingDependency, fmt.Errorf("%v depends on missing dependency", factory.factoryType), err)
-		return
+	plan := c.getFactoryPlan(factory)
+	if plan == nil {
+		if e = c.checkMissingDependencies(key, factory, ctx); e != nil {
+			return
+		}
 	}
 
-	// key := factory.key
-	// factoryType := factory.factoryType // unique by factory
-	fid := factory.id // unique by factory
+	if c.maxDepth > 0 && getCreationPath(ctx).size() >= c.maxDepth {
+		e = &ResolutionError{
+			Kind: ResolutionMaxDepth,
+			Key:  key,
+			Path: resolutionPath(ctx, ResolutionStep{Key: key, Factory: factory}),
+			Err:  ErrMaxDepthExceeded,
+		}
+		return
+	}
 
+	created := false
 	createObject := func() (out any, disposer DisposableAdapter, err error) {
-		defer func() {
-			ctx = c.afterCreation(fid, ctx)
+		start := time.Now()
+		creationCtx := c.beforeCreation(key, factory, ctx)
+
+		if profiler := getStartupProfiler(creationCtx); profiler != nil {
+			var span Span
+			creationCtx, span = profiler.Start(creationCtx, factory)
+			defer func() {
+				span.End(err)
+			}()
+		}
+
+		if c.tracer != nil {
+			var span Span
+			creationCtx, span = c.tracer.Start(creationCtx, factory)
+			defer func() {
+				span.End(err)
+			}()
+		}
 
+		defer func() {
 			if err == nil && factory.ReturnsValue() && out != nil {
 				// instance created - initializers/post construct
 				if i, ok := out.(Initializable); ok {
@@ -587,32 +1012,59 @@ func (c *container) createObject(factory *Factory, ctx context.Context, managed
 					}
 				}
 			}
+
+			if err == nil && c.logEnabled(LogCreation) {
+				c.log(LogCreation, "[di] component created",
+					slog.String("key", factory.String()),
+					slog.String("scope", factory.scope),
+					slog.Duration("duration", time.Since(start)),
+				)
+			}
+
+			if err == nil && c.metrics != nil {
+				c.metrics.ComponentCreated(factory, time.Since(start))
+			}
+
+			// references are not created by the container
+			created = err == nil && !factory.isReference && factory.ReturnsValue()
 		}()
-		ctx = c.beforeCreation(fid, ctx)
 
-		// args
-		var args []reflect.Value
-		if args, err = c.ResolveArgs(factory, ctx); err != nil {
-			return
+		if wiring := factory.getWiring(); wiring != nil {
+			// generated by cmd/di-gen, resolves the args with typed calls
+			out, err = wiring(c, creationCtx)
+		} else {
+			// args
+			var args []reflect.Value
+			if args, err = c.resolveArgs(factory, plan, creationCtx); err != nil {
+				return
+			}
+			out, err = factory.Create(args)
 		}
-
-		if out, err = factory.Create(args); err != nil {
+		if err != nil {
+			if !errors.As(err, new(*ResolutionError)) {
+				err = &ResolutionError{
+					Kind: ResolutionFactoryError,
+					Key:  key,
+					Path: resolutionPath(creationCtx),
+					Err:  err,
+				}
+			}
 			return
 		} else if out != nil {
 			disposer = &disposableAdapterImpl{
 				obj:       out,
 				factory:   factory,
 				container: c,
-				getContext: func() context.Context {
-					return ctx
-				},
+				ctx:       ctx,
 			}
 		}
 
 		return
 	}
 
-	if managed {
+	if managed && plan != nil {
+		instance, e = plan.scope.Get(ctx, factory, createObject)
+	} else if managed {
 		scopeName := factory.scope
 
 		if scopeName == "" {
@@ -632,51 +1084,60 @@ func (c *container) createObject(factory *Factory, ctx context.Context, managed
 		instance, disposer, e = createObject()
 	}
 
+	if created && e == nil {
+		c.publishLifecycle(ctx, ComponentCreated{Factory: factory, Instance: instance})
+	}
 	return
 }
 
 // ResolveArgs returns an ordered list of values which may be passed directly to the Factory Create method
 func (c *container) ResolveArgs(factory *Factory, contexts ...context.Context) ([]reflect.Value, error) {
-	ctx := getContext(contexts...)
-	args := make([]reflect.Value, len(factory.parameters))
-	for i, param := range factory.parameters {
-		paramKey := param.Key()
-		if paramKey == _keyContext {
+	return c.resolveArgs(factory, c.getFactoryPlan(factory), getContext(contexts...))
+}
+
+func (c *container) resolveArgs(factory *Factory, plan *resolutionPlan, ctx context.Context) ([]reflect.Value, error) {
+	var sources []argPlan
+	if plan != nil {
+		sources = plan.args
+	} else {
+		sources = compileArgs(factory)
+	}
+
+	args := make([]reflect.Value, len(sources))
+	for i, arg := range sources {
+		switch arg.kind {
+		case argContext:
 			args[i] = reflect.ValueOf(ctx)
-		} else if paramKey == _keyContainer {
+		case argContainer:
 			args[i] = reflect.ValueOf(c)
-		} else if param.Provider() {
-
-			// async get
-			objectFactory := c.GetObjectFactoryFor(param.Value(), !param.Unmanaged())
-			if param.Unmanaged() {
-				// user will be responsible for cleaning them up (call disposable.Dispose())
-				args[i] = param.ValueOf(func() (any, DisposableAdapter, error) {
-					object, disposable, err := objectFactory()
-					return object, disposable, err
-				})
-			} else {
-				// managed by scope (Ex. Request Scoped will destroy any Scoped("request"))
-				args[i] = param.ValueOf(func() (any, error) {
-					object, _, err := objectFactory()
-					return object, err
-				})
+		case argUnmanaged:
+			if err := c.checkProviderVisibility(arg.key, ctx); err != nil {
+				return nil, err
 			}
-
-		} else {
-			isQualifier := param.Qualified()
-
-			if isQualifier {
-				paramKey = param.Value()
+			// async get, user will be responsible for cleaning them up (call disposable.Dispose())
+			objectFactory := c.GetObjectFactoryFor(arg.key, false)
+			args[i] = arg.param.ValueOf(func() (any, DisposableAdapter, error) {
+				object, disposable, err := objectFactory()
+				return object, disposable, err
+			})
+		case argProvider:
+			if err := c.checkProviderVisibility(arg.key, ctx); err != nil {
+				return nil, err
 			}
-
-			value, err := c.Get(paramKey, ctx)
+			// async get, managed by scope (Ex. Request Scoped will destroy any Scoped("request"))
+			objectFactory := c.GetObjectFactoryFor(arg.key, true)
+			args[i] = arg.param.ValueOf(func() (any, error) {
+				object, _, err := objectFactory()
+				return object, err
+			})
+		default:
+			value, err := c.Get(arg.key, ctx)
 			if err != nil {
 				return nil, err
 			}
 
-			if isQualifier {
-				args[i] = param.ValueOf(value)
+			if arg.kind == argQualified {
+				args[i] = arg.param.ValueOf(value)
 			} else {
 				args[i] = reflect.ValueOf(value)
 			}
@@ -686,88 +1147,129 @@ func (c *container) ResolveArgs(factory *Factory, contexts ...context.Context) (
 }
 
 // Checks that all direct dependencies of the provided parameters are present in
-// the container. Returns an error if not.
-func (c *container) checkMissingDependencies(f *Factory) error {
-
-	var missingDeps []string
-
+// the container. Returns an error (for the first missing dependency) if not.
+func (c *container) checkMissingDependencies(key reflect.Type, f *Factory, ctx context.Context) error {
+	var missing []*Parameter
 	for _, param := range f.parameters {
-		paramKey := param.Key()
-		if paramKey == _keyContext || paramKey == _keyContainer {
-			// ignore context.Context and Container
-			continue
+		if c.isMissing(param) {
+			missing = append(missing, param)
 		}
+	}
+	if len(missing) > 0 {
+		return c.missingDependencyError(key, f, missing, ctx)
+	}
+	return nil
+}
 
-		// allProviders := c.factories[paramKey]
-		// This means that there is no factory that provides this value,
-		// and it is NOT being decorated and is NOT optional.
-		// In the case that there is no providers but there is a decorated value
-		// of this type, it can be provided safely so we can safely skip this.
-		if !param.HasCandidates() {
-			if c.testingHasMock { // see Mock
-				if _, ok := c.testingMocks[paramKey]; ok {
-					continue
-				}
-			}
+// isMissing checks if there is no candidate for the parameter in this container (or its parent)
+func (c *container) isMissing(param *Parameter) bool {
+	paramKey := param.Key()
+	if paramKey == _keyContext || paramKey == _keyContainer {
+		// ignore context.Context and Container
+		return false
+	}
 
-			// Check if component exists in this container
-			if c.parent != nil && c.parent.ContainsRecursive(paramKey) {
-				continue
-			}
+	// allProviders := c.factories[paramKey]
+	// This means that there is no factory that provides this value,
+	// and it is NOT being decorated and is NOT optional.
+	// In the case that there is no providers but there is a decorated value
+	// of this type, it can be provided safely so we can safely skip this.
+	if param.HasCandidates() || c.isMocked(paramKey) {
+		return false
+	}
 
-			missingDeps = append(missingDeps, fmt.Sprintf("%v", paramKey))
-		}
+	// Check if component exists in this container
+	if c.parent != nil && c.parent.ContainsRecursive(paramKey) {
+		return false
 	}
 
-	if len(missingDeps) == 0 {
-		return nil
+	return true
+}
+
+// missingDependencyError the path leads to the first missing parameter, all of them are
+// listed in Missing
+func (c *container) missingDependencyError(key reflect.Type, f *Factory, missing []*Parameter, ctx context.Context) error {
+	paramKey := missing[0].Key()
+	err := &ResolutionError{
+		Kind:        ResolutionNoCandidate,
+		Key:         paramKey,
+		Path:        resolutionPath(ctx, ResolutionStep{Key: key, Factory: f}, ResolutionStep{Key: paramKey}),
+		Suggestions: c.suggest(missing[0]),
+		Err:         ErrMissingDependency,
+	}
+	for _, param := range missing {
+		err.Missing = append(err.Missing, param.Key())
 	}
+	return err
+}
+
+// creationPath is an immutable list of the factories currently in creation
+// (from the requested root to the current one), stored in the context.
+type creationPath struct {
+	key     reflect.Type
+	factory *Factory
+	parent  *creationPath
+	depth   int
+}
 
-	return errors.New("missing dependencies: " + strings.Join(missingDeps, ", "))
+// getCreationPath returns the factories currently in creation in this context
+func getCreationPath(ctx context.Context) *creationPath {
+	path, _ := ctx.Value(ctxCurrentInCreationKey).(*creationPath)
+	return path
 }
 
-// isInCreation return whether the specified key is currently in creation.
-func (c *container) isInCreation(fid int, ctx context.Context) bool {
-	if curVal := ctx.Value(ctxCurrentInCreationKey); curVal != nil {
-		if curVal == fid {
+// size the number of factories in creation
+func (p *creationPath) size() int {
+	if p == nil {
+		return 0
+	}
+	return p.depth
+}
+
+// contains return whether the specified factory is currently in creation.
+func (p *creationPath) contains(fid int) bool {
+	for ; p != nil; p = p.parent {
+		if p.factory.id == fid {
 			return true
-		} else if keyMap, isType := curVal.(map[int]bool); isType {
-			return keyMap[fid]
 		}
 	}
 	return false
 }
 
-// beforeCreation callback before object creation. Registers the key as currently in creation.
-func (c *container) beforeCreation(fid int, ctx context.Context) context.Context {
-	curVal := ctx.Value(ctxCurrentInCreationKey)
-	if curVal == nil {
-		keyMap := map[int]bool{}
-		keyMap[fid] = true
-		return context.WithValue(ctx, ctxCurrentInCreationKey, keyMap)
-	} else {
-		curVal.(map[int]bool)[fid] = true
-		return ctx
+// factories returns the list of factories in creation, root first.
+func (p *creationPath) factories() []*Factory {
+	var list []*Factory
+	for ; p != nil; p = p.parent {
+		list = append(list, p.factory)
 	}
+	slices.Reverse(list)
+	return list
 }
 
-// afterCreation callback after object creation. Marks the key as not in creation anymore.
-func (c *container) afterCreation(fid int, ctx context.Context) context.Context {
-	curVal := ctx.Value(ctxCurrentInCreationKey)
-	if keyMap, isType := curVal.(map[int]bool); isType {
-		delete(keyMap, fid)
-		if len(keyMap) == 0 {
-			return context.WithValue(ctx, ctxCurrentInCreationKey, nil)
-		}
+// resolutionPath returns the resolution path of the context (root first), followed by the given steps
+func resolutionPath(ctx context.Context, steps ...ResolutionStep) []ResolutionStep {
+	var path []ResolutionStep
+	for p := getCreationPath(ctx); p != nil; p = p.parent {
+		path = append(path, ResolutionStep{Key: p.key, Factory: p.factory})
 	}
-	return ctx
+	slices.Reverse(path)
+	return append(path, steps...)
+}
+
+// beforeCreation callback before object creation. Registers the factory as currently in creation.
+func (c *container) beforeCreation(key reflect.Type, factory *Factory, ctx context.Context) context.Context {
+	parent := getCreationPath(ctx)
+	return context.WithValue(ctx, ctxCurrentInCreationKey, &creationPath{
+		key:     key,
+		factory: factory,
+		parent:  parent,
+		depth:   parent.size() + 1,
+	})
 }
 
 func (c *container) Contains(key reflect.Type) bool {
-	if c.testingHasMock {
-		if _, ok := c.testingMocks[key]; ok {
-			return true
-		}
+	if c.isMocked(key) {
+		return true
 	}
 
 	param := c.GetParam(key)
@@ -780,10 +1282,8 @@ func (c *container) ContainsRecursive(key reflect.Type) bool {
 		return true
 	}
 
-	if c.testingHasMock {
-		if _, ok := c.testingMocks[key]; ok {
-			return true
-		}
+	if c.isMocked(key) {
+		return true
 	}
 
 	if c.GetParam(key).HasCandidates() {
@@ -796,15 +1296,13 @@ func (c *container) ContainsRecursive(key reflect.Type) bool {
 	return false
 }
 
-func (c *container) resolveFactory(p *Parameter) (*Factory, error) {
+func (c *container) resolveFactory(p *Parameter, ctx context.Context) (*Factory, error) {
 
 	key := p.Key()
 
 	// see Mock
-	if c.testingHasMock {
-		if fn, ok := c.testingMocks[key]; ok {
-			return &Factory{mock: fn}, nil
-		}
+	if fn, ok := c.getMock(key); ok {
+		return &Factory{mock: fn}, nil
 	}
 
 	// Get candidates
@@ -823,7 +1321,13 @@ func (c *container) resolveFactory(p *Parameter) (*Factory, error) {
 
 	switch len(candidates) {
 	case 0:
-		return nil, errors.Join(fmt.Errorf("no candidate found for type %v", key), ErrCandidateNotFound)
+		return nil, &ResolutionError{
+			Kind:        ResolutionNoCandidate,
+			Key:         key,
+			Path:        resolutionPath(ctx, ResolutionStep{Key: key}),
+			Suggestions: c.suggest(p),
+			Err:         ErrCandidateNotFound,
+		}
 	case 1:
 		return candidates[0], nil
 	default:
@@ -839,6 +1343,11 @@ func (c *container) resolveFactory(p *Parameter) (*Factory, error) {
 			return first, nil
 		}
 
+		if first.Fake() && !second.Fake() {
+			// see WithFakes
+			return first, nil
+		}
+
 		if first.Primary() {
 			if !second.Primary() || first.Order() < second.Order() {
 				// If exactly one 'primary' component exists among the candidates, it
@@ -856,13 +1365,72 @@ func (c *container) resolveFactory(p *Parameter) (*Factory, error) {
 			return first, nil
 		}
 
-		return nil, errors.Join(fmt.Errorf("multiple candidates for type %v", key), ErrManyCandidates)
+		return nil, &ResolutionError{
+			Kind:       ResolutionManyCandidates,
+			Key:        key,
+			Path:       resolutionPath(ctx, ResolutionStep{Key: key}),
+			Candidates: candidates,
+			Err:        ErrManyCandidates,
+		}
 	}
 }
 
+// suggest returns "did you mean" suggestions for a parameter without candidates
+func (c *container) suggest(p *Parameter) (suggestions []string) {
+	key := p.Key()
+	for k, factories := range c.factories {
+		if len(factories) == 0 || k == _typeNilReturn {
+			continue
+		}
+
+		var hint string
+		switch {
+		case k.Kind() == reflect.Pointer && k.Elem() == key:
+			hint = "a pointer"
+		case key.Kind() == reflect.Pointer && key.Elem() == k:
+			hint = "not a pointer"
+		case key.Kind() == reflect.Interface && k.Kind() != reflect.Pointer && reflect.PointerTo(k).Implements(key):
+			hint = fmt.Sprintf("only *%v implements %v", k, key)
+		case p.Qualified() && k.AssignableTo(p.Value()):
+			hint = fmt.Sprintf("without qualifier %v", p.Qualifier())
+		case sameNameOtherPackage(k, key):
+			hint = "same name, different package"
+		default:
+			continue
+		}
+		suggestions = append(suggestions, fmt.Sprintf("%v (%s)", k, hint))
+	}
+	sort.Strings(suggestions)
+	return
+}
+
+// sameNameOtherPackage ex. pkg1.T and pkg2.T, *pkg1.T and *pkg2.T
+func sameNameOtherPackage(a, b reflect.Type) bool {
+	if a.Kind() == reflect.Pointer && b.Kind() == reflect.Pointer {
+		a, b = a.Elem(), b.Elem()
+	}
+	return a.Name() != "" && a.Name() == b.Name() && a.PkgPath() != b.PkgPath()
+}
+
 func (c *container) Destroy() error {
+	if c.graph == nil {
+		// already destroyed
+		return nil
+	}
+
+	c.publishLifecycle(context.Background(), ShutdownStarted{Container: c})
+	c.asyncMu.Lock()
+	c.asyncStopped = true
+	c.asyncMu.Unlock()
+
+	deadline := time.Now().Add(c.shutdownTimeout)
+	err := c.stopWorkers(deadline)
+	if !waitUntil(&c.asyncWg, deadline) {
+		err = errors.Join(err, fmt.Errorf("%w: async event listeners", ErrShutdownTimeout))
+	}
+
 	for name, scope := range c.scopes {
-		if name == SCOPE_SINGLETON || name == SCOPE_PROTOTYPE {
+		if name == SCOPE_SINGLETON || name == SCOPE_PROTOTYPE || c.sharedScopes[name] {
 			continue
 		}
 		scope.Destroy()
@@ -877,7 +1445,7 @@ func (c *container) Destroy() error {
 	c.singletons = nil
 	c.testingMocks = nil
 
-	return nil
+	return err
 }
 
 func (c *container) DestroyObject(key reflect.Type, object any) error {
@@ -890,35 +1458,41 @@ func (c *container) DestroySingletons() error {
 
 // Mock allows mocking of a dependency. Accepts "any", "func() any" or "func(context.Context) any"
 func (c *container) Mock(mock any) (cleanup func()) {
-	if !testing.Testing() {
-		panic("mocks are only allowed during testing")
-	}
-
 	var fn mockFunc
 	var key reflect.Type
 
 	if tFunc, isFunc := mock.(func() any); isFunc {
 		key = reflect.PointerTo(reflect.TypeOf(mock).Out(0))
-		fn = func(ctx context.Context) any {
-			return tFunc()
+		fn = func(ctx context.Context) (any, error) {
+			return tFunc(), nil
 		}
 	} else if tFuncCtx, isFuncCtx := mock.(func(ctx context.Context) any); isFuncCtx {
 		key = reflect.PointerTo(reflect.TypeOf(mock).Out(0))
-		fn = func(ctx context.Context) any {
-			return tFuncCtx(ctx)
+		fn = func(ctx context.Context) (any, error) {
+			return tFuncCtx(ctx), nil
 		}
 	} else {
 		key = reflect.PointerTo(reflect.TypeOf(mock))
-		fn = func(ctx context.Context) any {
-			return mock
+		fn = func(ctx context.Context) (any, error) {
+			return mock, nil
 		}
 	}
 
+	return c.mock(key, fn)
+}
+
+// mock registers the mock of the key (see Mock and MockFor)
+func (c *container) mock(key reflect.Type, fn mockFunc) (cleanup func()) {
+	if !c.mockAllowed {
+		panic("mocks are only allowed during testing")
+	}
+
 	c.mockMu.Lock()
 	defer c.mockMu.Unlock()
 
 	c.testingHasMock = true
 	c.testingMocks[key] = fn
+	c.resetPlans()
 
 	// cleanup
 	cleanup = func() {
@@ -927,6 +1501,7 @@ func (c *container) Mock(mock any) (cleanup func()) {
 
 		delete(c.testingMocks, key)
 		c.testingHasMock = len(c.testingMocks) > 0
+		c.resetPlans()
 	}
 	return
 }
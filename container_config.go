@@ -0,0 +1,161 @@
+package di
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// ContainerConfig is the type to configure the Container.
+// New accepts any number of config (this is functional option pattern).
+type ContainerConfig func(*container)
+
+// LogEvent type of event logged by the container
+type LogEvent uint8
+
+const (
+	LogRegistration LogEvent = iota // a component was registered
+	LogCandidate                    // a component is a candidate (alias) for another type
+	LogCreation                     // a component was created (with timing)
+	LogDisposal                     // a component was disposed (with timing)
+	LogError                        // initialization or resolution error
+)
+
+// defaultLogLevels by default, only the errors are visible with the default slog handler
+// (level INFO), the high-volume events are logged on DEBUG.
+var defaultLogLevels = map[LogEvent]slog.Level{
+	LogRegistration: slog.LevelDebug,
+	LogCandidate:    slog.LevelDebug,
+	LogCreation:     slog.LevelDebug,
+	LogDisposal:     slog.LevelDebug,
+	LogError:        slog.LevelError,
+}
+
+// WithLogger sets the logger used by the container. Defaults to slog.Default()
+//
+// Example:
+//
+//	ctn := di.New(nil, di.WithLogger(logger))
+func WithLogger(logger *slog.Logger) ContainerConfig {
+	return func(c *container) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevel sets the level used to log the given event type.
+//
+// Example:
+//
+//	// logs candidates on INFO
+//	ctn := di.New(nil, di.WithLogLevel(di.LogCandidate, slog.LevelInfo))
+func WithLogLevel(event LogEvent, level slog.Level) ContainerConfig {
+	return func(c *container) {
+		c.logLevels[event] = level
+	}
+}
+
+// WithStrictMode only exact matches are injected. Implicit candidates (Ex. *MyServiceImpl
+// for a MyService parameter) are ignored.
+func WithStrictMode() ContainerConfig {
+	return func(c *container) {
+		c.strict = true
+	}
+}
+
+// WithDefaultScope sets the scope of the components registered without an explicit
+// scope. Defaults to SCOPE_SINGLETON. Startup and Eager components are singletons,
+// whatever the default scope.
+//
+// See Scoped
+func WithDefaultScope(scope string) ContainerConfig {
+	scope = strings.TrimSpace(scope)
+	if scope == "" {
+		scope = SCOPE_SINGLETON
+	}
+	return func(c *container) {
+		c.defaultScope = scope
+	}
+}
+
+// WithLazyDefault sets if singletons without Lazy or Eager config are created only when
+// requested (true, the default) or during the container initialization (false).
+//
+// See Lazy, Eager
+func WithLazyDefault(lazy bool) ContainerConfig {
+	return func(c *container) {
+		c.lazyDefault = lazy
+	}
+}
+
+// WithMaxDepth sets the max resolution depth (the number of components in a resolution
+// path). Defaults to 0 (unlimited)
+func WithMaxDepth(depth int) ContainerConfig {
+	return func(c *container) {
+		c.maxDepth = depth
+	}
+}
+
+// WithMock sets if Container.Mock is allowed. Defaults to testing.Testing()
+func WithMock(allowed bool) ContainerConfig {
+	return func(c *container) {
+		c.mockAllowed = allowed
+	}
+}
+
+// WithParentFactories registers all the components of the parent container (see New),
+// without their instances. The components are created by the new container, which also
+// resolves their dependencies: registrations and mocks of the new container apply to the
+// dependencies of the parent components too. Only the components registered in the
+// parent up to this moment are copied.
+//
+// Example:
+//
+//	ctn := di.New(di.Global(), di.WithParentFactories())
+//	ctn.Mock(&FakeRepository{})
+func WithParentFactories() ContainerConfig {
+	return func(c *container) {
+		if parent, ok := c.parent.(*container); ok {
+			c.copyFactories(parent)
+		}
+	}
+}
+
+// WithParallelInitialization initializes the startup components concurrently, using
+// up to workers goroutines (<= 0 uses runtime.GOMAXPROCS).
+//
+// Components with different Order are initialized in sequence (by order), components
+// with the same Order are grouped by dependency level: a component starts only after
+// the startup components it depends on. The first error is returned by Initialize, the
+// components not started yet are skipped.
+func WithParallelInitialization(workers int) ContainerConfig {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return func(c *container) {
+		c.initParallel = true
+		c.initWorkers = workers
+	}
+}
+
+// logEnabled checks if the event is logged
+func (c *container) logEnabled(event LogEvent) bool {
+	return c.getLogger().Enabled(context.Background(), c.logLevels[event])
+}
+
+// log the event, if enabled
+func (c *container) log(event LogEvent, msg string, attrs ...slog.Attr) {
+	logger := c.getLogger()
+	level := c.logLevels[event]
+	if !logger.Enabled(context.Background(), level) {
+		return
+	}
+	logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (c *container) getLogger() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return slog.Default()
+}
@@ -86,6 +86,10 @@ func (c *container) Filter(options ...FactoryConfig) *FilteredFactories {
 				continue
 			}
 
+			if filter.module != "" && filter.module != factory.module {
+				continue
+			}
+
 			if len(filter.qualifiers) > 0 {
 				found := false
 				for qualifier := range filter.qualifiers {
@@ -114,15 +118,21 @@ func (c *container) Filter(options ...FactoryConfig) *FilteredFactories {
 // the following order:
 //
 // 1) Mock (test)
-// 2) Primary
-// 3) NOT Alternative
-// 4) Lower Order
+// 2) Fake (see WithFakes)
+// 3) Primary
+// 4) NOT Alternative
+// 5) Lower Order
 func DefaultFactorySortLessFn(a, b *Factory) bool {
 	if a.Mock() != b.Mock() {
 		// mock first (testing)
 		return a.Mock()
 	}
 
+	if a.Fake() != b.Fake() {
+		// see WithFakes
+		return a.Fake()
+	}
+
 	if a.Primary() != b.Primary() {
 		return a.Primary()
 	}
@@ -0,0 +1,166 @@
+package di
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DependencyGraph is a snapshot of the factories registered in a container
+// (nodes) and the dependencies between them (edges).
+//
+// Example:
+//
+//	g := di.Graph()
+//	os.WriteFile("components.dot", []byte(g.DOT()), 0644)
+type DependencyGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+// GraphNode represents a Factory in the DependencyGraph
+type GraphNode struct {
+	Id          int      `json:"id"`                   // factory unique id
+	Key         string   `json:"key"`                  // component key
+	Constructor string   `json:"constructor"`          // constructor function name
+	Scope       string   `json:"scope"`                // scope name
+	Order       int      `json:"order"`                // factory order
+	Startup     bool     `json:"startup,omitempty"`    // initialized with the container
+	Qualifiers  []string `json:"qualifiers,omitempty"` // component qualifiers
+	Factory     *Factory `json:"-"`
+}
+
+// GraphEdge represents a dependency (constructor parameter) between two nodes.
+// From depends on To.
+type GraphEdge struct {
+	From  int    `json:"from"`  // id of the dependent factory
+	To    int    `json:"to"`    // id of the factory that satisfies the parameter
+	Param string `json:"param"` // parameter key
+	Exact bool   `json:"exact"` // true when To exactly matches the parameter, false for a candidate (alias)
+}
+
+func (c *container) Graph() *DependencyGraph {
+	var factories []*Factory
+	for _, list := range c.factories {
+		factories = append(factories, list...)
+	}
+	sort.Slice(factories, func(i, j int) bool {
+		return factories[i].id < factories[j].id
+	})
+
+	g := &DependencyGraph{}
+
+	for _, f := range factories {
+		node := GraphNode{
+			Id:      f.id,
+			Key:     f.key.String(),
+			Scope:   f.scope,
+			Order:   f.order,
+			Startup: f.startup,
+			Factory: f,
+		}
+		if !f.isReference {
+			node.Constructor = funcName(f.factoryValue)
+		}
+		for _, qualifier := range f.Qualifiers() {
+			node.Qualifiers = append(node.Qualifiers, qualifier.String())
+		}
+		sort.Strings(node.Qualifiers)
+		g.Nodes = append(g.Nodes, node)
+	}
+
+	for _, f := range factories {
+		for _, param := range f.parameters {
+			if param.key == _keyContext || param.key == _keyContainer {
+				continue
+			}
+			for _, dep := range factories {
+				if dep.key == _typeNilReturn {
+					continue
+				}
+				if isCandidate, isExactMatch := param.IsValidCandidate(dep); isCandidate {
+					g.Edges = append(g.Edges, GraphEdge{
+						From:  f.id,
+						To:    dep.id,
+						Param: param.key.String(),
+						Exact: isExactMatch,
+					})
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+// DOT exports the graph in the Graphviz DOT language
+func (g *DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph di {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box];\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\tn%d [label=%s];\n", n.Id, dotQuote(strings.Join(n.lines(), "\n")))
+	}
+	for _, e := range g.Edges {
+		style := ""
+		if !e.Exact {
+			style = ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\tn%d -> n%d [label=%s%s];\n", e.From, e.To, dotQuote(e.Param), style)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid exports the graph as a Mermaid flowchart
+func (g *DependencyGraph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "\tn%d[\"%s\"]\n", n.Id, mermaidEscape(strings.Join(n.lines(), "<br/>")))
+	}
+	for _, e := range g.Edges {
+		if e.Exact {
+			fmt.Fprintf(&b, "\tn%d -- \"%s\" --> n%d\n", e.From, mermaidEscape(e.Param), e.To)
+		} else {
+			fmt.Fprintf(&b, "\tn%d -. \"%s\" .-> n%d\n", e.From, mermaidEscape(e.Param), e.To)
+		}
+	}
+	return b.String()
+}
+
+// JSON exports the graph as indented JSON
+func (g *DependencyGraph) JSON() ([]byte, error) {
+	return json.MarshalIndent(g, "", "  ")
+}
+
+// lines human readable description of the node
+func (n GraphNode) lines() []string {
+	title := n.Key
+	if n.Factory != nil && n.Factory.key == _typeNilReturn {
+		title = n.Constructor
+	}
+	lines := []string{title, n.Scope}
+	if n.Startup {
+		lines[1] += fmt.Sprintf(", startup(%d)", n.Order)
+	} else if n.Order != 0 {
+		lines[1] += fmt.Sprintf(", order(%d)", n.Order)
+	}
+	for _, q := range n.Qualifiers {
+		lines = append(lines, "@"+q)
+	}
+	return lines
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
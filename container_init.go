@@ -0,0 +1,147 @@
+package di
+
+import (
+	"context"
+	"sync"
+)
+
+// initializeParallel creates the startup components concurrently (see WithParallelInitialization)
+func (c *container) initializeParallel(ctx context.Context, factories []*Factory) error {
+	for _, batch := range c.initializationBatches(factories, ctx) {
+		if err := c.initializeBatch(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initializeBatch creates the factories of the batch using a bounded worker pool.
+// Returns the first error, the factories not started yet are skipped. The components
+// receive the context of Initialize, it is not canceled by the error.
+func (c *container) initializeBatch(ctx context.Context, batch []*Factory) error {
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		first error
+	)
+
+	failed := make(chan struct{}) // closed on the first error
+	stopped := func() bool {
+		select {
+		case <-failed:
+			return true
+		default:
+			return ctx.Err() != nil
+		}
+	}
+
+	tasks := make(chan *Factory)
+	for i := 0; i < min(c.initWorkers, len(batch)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for f := range tasks {
+				if stopped() {
+					continue
+				}
+				if _, _, err := c.GetObjectFactory(f, true, ctx)(); err != nil {
+					once.Do(func() {
+						first = err
+						close(failed)
+					})
+				}
+			}
+		}()
+	}
+
+	for _, f := range batch {
+		if stopped() {
+			break
+		}
+		tasks <- f
+	}
+	close(tasks)
+	wg.Wait()
+
+	if first == nil {
+		first = ctx.Err()
+	}
+	return first
+}
+
+// initializationBatches groups the factories (already sorted) by Order and, inside
+// each group, by dependency level. A factory is placed after all factories of the
+// same group it depends on (directly or through other components).
+func (c *container) initializationBatches(factories []*Factory, ctx context.Context) (batches [][]*Factory) {
+	for start := 0; start < len(factories); {
+		end := start + 1
+		for end < len(factories) && factories[end].order == factories[start].order {
+			end++
+		}
+		group := factories[start:end]
+		start = end
+
+		inGroup := map[*Factory]bool{}
+		for _, f := range group {
+			inGroup[f] = true
+		}
+
+		levels := map[*Factory]int{}
+		var levelOf func(f *Factory, visiting map[*Factory]bool) int
+		levelOf = func(f *Factory, visiting map[*Factory]bool) int {
+			if level, ok := levels[f]; ok {
+				return level
+			}
+			visiting[f] = true
+			defer delete(visiting, f)
+
+			level := 0
+			for _, dep := range c.dependenciesOf(f, ctx) {
+				if visiting[dep] {
+					continue
+				}
+				if l := levelOf(dep, visiting); inGroup[dep] {
+					level = max(level, l+1)
+				} else {
+					// indirect dependency, through a component outside the group
+					level = max(level, l)
+				}
+			}
+			levels[f] = level
+			return level
+		}
+
+		var levelBatches [][]*Factory
+		for _, f := range group {
+			level := levelOf(f, map[*Factory]bool{})
+			for len(levelBatches) <= level {
+				levelBatches = append(levelBatches, nil)
+			}
+			levelBatches[level] = append(levelBatches[level], f)
+		}
+		for _, batch := range levelBatches {
+			if len(batch) > 0 {
+				batches = append(batches, batch)
+			}
+		}
+	}
+	return
+}
+
+// dependenciesOf the factories selected for the parameters of f in this container.
+// Providers are ignored, they are resolved on demand.
+func (c *container) dependenciesOf(f *Factory, ctx context.Context) (deps []*Factory) {
+	for _, param := range f.parameters {
+		if param.Provider() {
+			continue
+		}
+		key := resolveKeyOf(param)
+		if key == _keyContext || key == _keyContainer || c.isMocked(key) || !c.Contains(key) {
+			continue
+		}
+		if dep, err := c.resolveFactory(c.GetParam(key), ctx); err == nil {
+			deps = append(deps, dep)
+		}
+	}
+	return
+}
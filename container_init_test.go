@@ -0,0 +1,129 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCacheA struct{}
+type testCacheB struct{}
+type testCacheC struct{}
+type testCacheWarmer struct{}
+
+func TestParallelInitialization(t *testing.T) {
+	var running, maxRunning atomic.Int32
+	var mu sync.Mutex
+	var finished []string
+
+	work := func(name string) {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		running.Add(-1)
+		mu.Lock()
+		finished = append(finished, name)
+		mu.Unlock()
+	}
+
+	ctn := New(nil, WithParallelInitialization(4))
+	ctn.Register(func() *testCacheA { work("a"); return &testCacheA{} }, Startup(1))
+	ctn.Register(func() *testCacheB { work("b"); return &testCacheB{} }, Startup(1))
+	ctn.Register(func(a *testCacheA) *testCacheC { work("c"); return &testCacheC{} }, Startup(1))
+	ctn.Register(func(b *testCacheB, c *testCacheC) *testCacheWarmer { work("warmer"); return &testCacheWarmer{} }, Startup(2))
+
+	require.NoError(t, ctn.Initialize())
+
+	// a and b run concurrently, c after a, warmer (Order 2) after all
+	require.Equal(t, int32(2), maxRunning.Load())
+	require.ElementsMatch(t, []string{"a", "b"}, finished[:2])
+	require.Equal(t, []string{"c", "warmer"}, finished[2:])
+}
+
+func TestParallelInitializationBatches(t *testing.T) {
+	ctn := New(nil, WithParallelInitialization(2)).(*container)
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ctn.Register(func(l *testResolveLeaf) *testResolveMiddle { return &testResolveMiddle{} }, Startup(1))
+	ctn.Register(func(m *testResolveMiddle) *testResolveRoot { return &testResolveRoot{} }, Startup(1))
+	ctn.Register(func() *testCacheA { return &testCacheA{} }, Startup(1))
+	ctn.Register(func() *testCacheB { return &testCacheB{} }, Startup(0))
+
+	ctn.refreshAliasAll()
+	batches := ctn.initializationBatches(ctn.Filter(initializersStereotype).factories, context.Background())
+
+	var keys [][]string
+	for _, batch := range batches {
+		var names []string
+		for _, f := range batch {
+			names = append(names, f.String())
+		}
+		keys = append(keys, names)
+	}
+	require.Len(t, keys, 3)
+	require.Equal(t, []string{"*di.testCacheB"}, keys[0])
+	require.ElementsMatch(t, []string{"*di.testResolveMiddle", "*di.testCacheA"}, keys[1])
+	require.Equal(t, []string{"*di.testResolveRoot"}, keys[2])
+}
+
+func TestParallelInitializationError(t *testing.T) {
+	errFail := errors.New("failed")
+	var finished atomic.Bool
+
+	ctn := New(nil, WithParallelInitialization(2))
+	ctn.Register(func() (*testCacheA, error) {
+		time.Sleep(10 * time.Millisecond)
+		return nil, errFail
+	}, Startup(1))
+	ctn.Register(func(ctx context.Context) *testCacheB {
+		time.Sleep(50 * time.Millisecond)
+		finished.Store(ctx.Err() == nil)
+		return &testCacheB{}
+	}, Startup(1))
+	created := false
+	ctn.Register(func() *testCacheC { created = true; return &testCacheC{} }, Startup(2))
+
+	err := ctn.Initialize()
+	require.ErrorIs(t, err, errFail)
+	require.True(t, finished.Load(), "the running components are not canceled")
+	require.False(t, created)
+
+	// one worker, the components are started one by one
+	var failed, skipped atomic.Bool
+	ctn = New(nil, WithParallelInitialization(1))
+	ctn.Register(func() (*testCacheA, error) { failed.Store(true); return nil, errFail }, Startup(1))
+	ctn.Register(func() *testCacheB { skipped.Store(failed.Load()); return &testCacheB{} }, Startup(1))
+	ctn.Register(func() *testCacheWarmer { skipped.Store(failed.Load()); return &testCacheWarmer{} }, Startup(1))
+
+	require.ErrorIs(t, ctn.Initialize(), errFail)
+	require.False(t, skipped.Load(), "the components not started are skipped")
+}
+
+func TestParallelInitializationContext(t *testing.T) {
+	var captured []context.Context
+	var mu sync.Mutex
+	capture := func(ctx context.Context) {
+		mu.Lock()
+		captured = append(captured, ctx)
+		mu.Unlock()
+	}
+
+	ctn := New(nil, WithParallelInitialization(2))
+	ctn.Register(func(ctx context.Context) *testCacheA { capture(ctx); return &testCacheA{} }, Startup(1))
+	ctn.Register(func(ctx context.Context) *testCacheB { capture(ctx); return &testCacheB{} }, Startup(1))
+	require.NoError(t, ctn.Initialize())
+
+	require.Len(t, captured, 2)
+	for _, ctx := range captured {
+		require.NoError(t, ctx.Err(), "the context is still valid after Initialize")
+	}
+}
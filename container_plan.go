@@ -0,0 +1,159 @@
+package di
+
+import (
+	"context"
+	"reflect"
+)
+
+// argKind how an argument of a factory is resolved
+type argKind uint8
+
+const (
+	argContext   argKind = iota // the context of the creation
+	argContainer                // the container
+	argValue                    // Get(key)
+	argQualified                // Qualified[T, Q], Get(key)
+	argProvider                 // Provider[T]
+	argUnmanaged                // Unmanaged[T]
+)
+
+// argPlan the source of an argument of a factory
+type argPlan struct {
+	kind  argKind
+	key   reflect.Type // the resolved key (the value type of providers and qualified)
+	param *Parameter
+}
+
+// resolutionPlan the precomputed resolution of a factory: the scope and the source of
+// each argument. Compiled only after the container is locked (see Initialize), when
+// the registrations can no longer change. Mock invalidates all plans.
+type resolutionPlan struct {
+	scope ScopeI
+	args  []argPlan
+}
+
+// compileArgs the argument sources of the factory
+func compileArgs(factory *Factory) []argPlan {
+	args := make([]argPlan, len(factory.parameters))
+	for i, param := range factory.parameters {
+		arg := argPlan{key: param.Key(), param: param}
+		switch {
+		case arg.key == _keyContext:
+			arg.kind = argContext
+		case arg.key == _keyContainer:
+			arg.kind = argContainer
+		case param.Unmanaged():
+			arg.kind, arg.key = argUnmanaged, param.Value()
+		case param.Provider():
+			arg.kind, arg.key = argProvider, param.Value()
+		case param.Qualified():
+			arg.kind, arg.key = argQualified, param.Value()
+		default:
+			arg.kind = argValue
+		}
+		args[i] = arg
+	}
+	return args
+}
+
+// getKeyPlan the factory selected for the key, resolved once after the container is
+// locked. Returns nil when the key must be resolved by the regular path (not locked,
+// provided by the parent, or resolution error).
+func (c *container) getKeyPlan(key reflect.Type) *Factory {
+	if !c.locked {
+		return nil
+	}
+
+	c.plansMu.RLock()
+	factory, exists := c.keyPlans[key]
+	generation := c.plansGeneration
+	c.plansMu.RUnlock()
+	if exists {
+		return factory
+	}
+
+	if c.parent != nil && !c.Contains(key) {
+		return nil
+	}
+
+	param := c.GetParam(key)
+	factory, err := c.resolveFactory(param, context.Background())
+	if err != nil {
+		return nil
+	}
+
+	c.plansMu.Lock()
+	if generation == c.plansGeneration {
+		c.keyPlans[key] = factory
+	}
+	c.plansMu.Unlock()
+	return factory
+}
+
+// getFactoryPlan the resolution plan of the factory, compiled once after the container
+// is locked. Returns nil when the factory must be resolved by the regular path (not
+// locked, missing dependencies or unregistered scope).
+func (c *container) getFactoryPlan(factory *Factory) *resolutionPlan {
+	if !c.locked || factory.mock != nil {
+		return nil
+	}
+
+	c.plansMu.RLock()
+	plan, exists := c.factoryPlans[factory]
+	generation := c.plansGeneration
+	c.plansMu.RUnlock()
+	if exists {
+		return plan
+	}
+
+	for _, param := range factory.parameters {
+		if c.isMissing(param) {
+			return nil
+		}
+	}
+
+	scope := c.scopes[factory.scope]
+	if scope == nil {
+		return nil
+	}
+
+	plan = &resolutionPlan{scope: scope, args: compileArgs(factory)}
+
+	c.plansMu.Lock()
+	if generation == c.plansGeneration {
+		c.factoryPlans[factory] = plan
+	}
+	c.plansMu.Unlock()
+	return plan
+}
+
+// compilePlans compiles the plans of all known keys and factories (see Initialize)
+func (c *container) compilePlans() {
+	c.paramsMu.RLock()
+	keys := make([]reflect.Type, 0, len(c.knownParams))
+	for key, param := range c.knownParams {
+		if param.HasCandidates() {
+			keys = append(keys, key)
+		}
+	}
+	c.paramsMu.RUnlock()
+
+	for _, key := range keys {
+		c.getKeyPlan(key)
+	}
+	for _, factories := range c.factories {
+		for _, factory := range factories {
+			c.getFactoryPlan(factory)
+		}
+	}
+}
+
+// resetPlans invalidates all plans (see Mock)
+func (c *container) resetPlans() {
+	c.plansMu.Lock()
+	defer c.plansMu.Unlock()
+	c.plansGeneration++
+	c.keyPlans = make(map[reflect.Type]*Factory)
+	c.factoryPlans = make(map[*Factory]*resolutionPlan)
+	c.listenerPlans = make(map[reflect.Type][]*Factory)
+}
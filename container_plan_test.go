@@ -0,0 +1,101 @@
+package di
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolutionPlan(t *testing.T) {
+	ctn := New(nil).(*container)
+	ctn.Register(func() testServiceA { return newTestServiceA("a", nil) }, Prototype)
+	ctn.Register(func() testServiceA { return newTestServiceA("b", nil) }, Prototype, Order(1))
+	ctn.Register(func(s testServiceA) *testResolveLeaf { return &testResolveLeaf{} }, Prototype)
+
+	// compiled after the container is locked
+	require.Empty(t, ctn.keyPlans)
+	require.NoError(t, ctn.Initialize())
+	require.NotNil(t, ctn.keyPlans[Key[testServiceA]()])
+	require.NotNil(t, ctn.factoryPlans[ctn.keyPlans[Key[*testResolveLeaf]()]])
+
+	s, err := GetFrom[testServiceA](ctn)
+	require.NoError(t, err)
+	require.Equal(t, "a", s.Name())
+
+	// mocks invalidate the plans
+	cleanup := ctn.Mock(testResolveLeaf{})
+	require.Empty(t, ctn.keyPlans)
+	leaf, err := ctn.Get(Key[*testResolveLeaf]())
+	require.NoError(t, err)
+	require.Equal(t, testResolveLeaf{}, leaf)
+
+	cleanup()
+	leaf, err = ctn.Get(Key[*testResolveLeaf]())
+	require.NoError(t, err)
+	require.Equal(t, &testResolveLeaf{}, leaf)
+
+	// unresolvable keys are not planned
+	_, err = GetFrom[*testResolveRoot](ctn)
+	require.ErrorIs(t, err, ErrCandidateNotFound)
+	require.NotContains(t, ctn.keyPlans, Key[*testResolveRoot]())
+}
+
+func benchmarkContainer(b *testing.B) Container {
+	ctn := New(nil)
+	if err := ctn.RegisterScope("request", &testRequestScope{}); err != nil {
+		b.Fatal(err)
+	}
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ctn.Register(func(l *testResolveLeaf) *testResolveMiddle { return &testResolveMiddle{} }, Prototype)
+	ctn.Register(func(m *testResolveMiddle, l *testResolveLeaf) *testResolveRoot { return &testResolveRoot{} }, Scoped("request"))
+	ctn.Register(func() testServiceA { return newTestServiceA("a", nil) }, Prototype)
+	ctn.Register(func() testServiceA { return newTestServiceA("b", nil) }, Prototype, Order(1))
+	if err := ctn.Initialize(); err != nil {
+		b.Fatal(err)
+	}
+	return ctn
+}
+
+func BenchmarkGetSingleton(b *testing.B) {
+	ctn := benchmarkContainer(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetFrom[*testResolveLeaf](ctn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetPrototype(b *testing.B) {
+	ctn := benchmarkContainer(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetFrom[*testResolveMiddle](ctn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetPrototypeManyCandidates(b *testing.B) {
+	ctn := benchmarkContainer(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetFrom[testServiceA](ctn); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetRequestScoped(b *testing.B) {
+	ctn := benchmarkContainer(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := GetFrom[*testResolveRoot](ctn, newTestRequest()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,7 +1,13 @@
 package di
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -197,3 +203,501 @@ func TestFullCycle(t *testing.T) {
 		"srv-3:Destroy", "srv-3:Disposer()",
 	}, logs())
 }
+
+type testCycleA struct{}
+type testCycleB struct{}
+type testCycleC struct{}
+
+func TestCycleError(t *testing.T) {
+	ctn := New(nil)
+
+	require.NoError(t, ctn.ShouldRegister(func(b *testCycleB) *testCycleA { return &testCycleA{} }))
+	require.NoError(t, ctn.ShouldRegister(func(c *testCycleC) *testCycleB { return &testCycleB{} }))
+
+	err := ctn.ShouldRegister(func(a *testCycleA) *testCycleC { return &testCycleC{} })
+	require.ErrorIs(t, err, ErrCycleDetected)
+
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Len(t, cycleErr.Path, 4)
+	require.Contains(t, err.Error(), "*di.testCycleA -> *di.testCycleB -> *di.testCycleC -> *di.testCycleA")
+	require.Contains(t, err.Error(), "container_test.go:")
+
+	// the rejected factory must not affect new registrations
+	require.NoError(t, ctn.ShouldRegister(func() *testCycleC { return &testCycleC{} }))
+}
+
+func TestCycleErrorInCreation(t *testing.T) {
+	ctn := New(nil)
+
+	// cycle through a candidate (alias), not visible during registration
+	ctn.Register(func(s testServiceA) *testCycleA { return &testCycleA{} })
+	ctn.Register(func(a *testCycleA) *testServiceAImpl { return &testServiceAImpl{} })
+
+	require.NoError(t, ctn.Initialize())
+
+	_, err := GetFrom[*testCycleA](ctn)
+	require.ErrorIs(t, err, ErrCurrentlyInCreation)
+
+	var cycleErr *CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	require.Contains(t, err.Error(), "*di.testCycleA -> *di.testServiceAImpl -> *di.testCycleA")
+}
+
+type testResolveRoot struct{}
+type testResolveMiddle struct{}
+type testResolveLeaf struct{}
+
+func TestResolutionError(t *testing.T) {
+	ctn := New(nil)
+
+	ctn.Register(func(m *testResolveMiddle) *testResolveRoot { return &testResolveRoot{} })
+	ctn.Register(func(s testServiceA) *testResolveMiddle { return &testResolveMiddle{} })
+
+	// only *testServiceAImpl implements testServiceA
+	ctn.Register(func() testServiceAImpl { return testServiceAImpl{} })
+
+	require.NoError(t, ctn.Initialize())
+
+	_, err := GetFrom[*testResolveRoot](ctn)
+	require.ErrorIs(t, err, ErrMissingDependency)
+
+	var resErr *ResolutionError
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, ResolutionNoCandidate, resErr.Kind)
+	require.Equal(t, Key[testServiceA](), resErr.Key)
+	require.Len(t, resErr.Path, 3)
+	require.Equal(t, Key[*testResolveRoot](), resErr.Path[0].Key)
+	require.Equal(t, Key[*testResolveMiddle](), resErr.Path[1].Key)
+	require.Equal(t, []string{"di.testServiceAImpl (only *di.testServiceAImpl implements di.testServiceA)"}, resErr.Suggestions)
+	require.Contains(t, err.Error(), "-> *di.testResolveMiddle")
+	require.Contains(t, err.Error(), "did you mean di.testServiceAImpl")
+}
+
+func TestResolutionErrorCandidates(t *testing.T) {
+	ctn := New(nil)
+
+	ctn.Register(func(s testServiceBase) *testResolveRoot { return &testResolveRoot{} })
+	ctn.Register(func() testServiceA { return newTestServiceA("a", nil) })
+	ctn.Register(func() testServiceB { return newTestServiceB("b", nil) })
+
+	require.NoError(t, ctn.Initialize())
+
+	_, err := GetFrom[*testResolveRoot](ctn)
+	require.ErrorIs(t, err, ErrManyCandidates)
+
+	var resErr *ResolutionError
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, ResolutionManyCandidates, resErr.Kind)
+	require.Len(t, resErr.Candidates, 2)
+	require.Len(t, resErr.Path, 2)
+}
+
+func TestResolutionErrorFactory(t *testing.T) {
+	ctn := New(nil)
+	errLeaf := errors.New("leaf failed")
+
+	ctn.Register(func(m *testResolveMiddle) *testResolveRoot { return &testResolveRoot{} })
+	ctn.Register(func(l *testResolveLeaf) *testResolveMiddle { return &testResolveMiddle{} })
+	ctn.Register(func() (*testResolveLeaf, error) { return nil, errLeaf })
+
+	require.NoError(t, ctn.Initialize())
+
+	_, err := GetFrom[*testResolveRoot](ctn)
+	require.ErrorIs(t, err, errLeaf)
+
+	var resErr *ResolutionError
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, ResolutionFactoryError, resErr.Kind)
+	require.Len(t, resErr.Path, 3)
+	require.NotNil(t, resErr.Path[2].Factory)
+
+	t.Run("startup function", func(t *testing.T) {
+		ctn := New(nil)
+		ctn.Register(testFailingStartup, Startup(0))
+
+		err := ctn.Initialize()
+		require.ErrorIs(t, err, errTestStartup)
+		require.Contains(t, err.Error(), "cannot resolve github.com/go-path/di.testFailingStartup: github.com/go-path/di.testFailingStartup constructor returned an error")
+		require.NotContains(t, err.Error(), "nilReturn")
+	})
+}
+
+var errTestStartup = errors.New("startup failed")
+
+func testFailingStartup() error {
+	return errTestStartup
+}
+
+func TestResolutionErrorMissingDependencies(t *testing.T) {
+	ctn := New(nil)
+	ctn.Register(func(m *testResolveMiddle, s testServiceA) *testResolveRoot { return &testResolveRoot{} })
+	require.NoError(t, ctn.Initialize())
+
+	_, err := GetFrom[*testResolveRoot](ctn)
+	require.ErrorIs(t, err, ErrMissingDependency)
+
+	var resErr *ResolutionError
+	require.ErrorAs(t, err, &resErr)
+	require.Equal(t, []reflect.Type{Key[*testResolveMiddle](), Key[testServiceA]()}, resErr.Missing)
+	require.Contains(t, err.Error(), "missing dependencies *di.testResolveMiddle, di.testServiceA")
+}
+
+func TestValidate(t *testing.T) {
+	ctn := New(nil)
+	called := false
+
+	ctn.Register(func(m *testResolveMiddle, s testServiceBase) *testResolveRoot {
+		called = true
+		return &testResolveRoot{}
+	})
+	ctn.Register(func() testServiceA {
+		called = true
+		return newTestServiceA("a", nil)
+	})
+	ctn.Register(func() testServiceB {
+		called = true
+		return newTestServiceB("b", nil)
+	}, Scoped("request"))
+
+	err := ctn.Validate()
+	require.False(t, called, "constructors must not be called")
+	require.ErrorIs(t, err, ErrMissingDependency)
+	require.ErrorIs(t, err, ErrManyCandidates)
+	require.ErrorIs(t, err, ErrNoScopeNameRegistered)
+
+	valid := New(nil)
+	valid.Register(func(s testServiceA) *testResolveRoot { return &testResolveRoot{} })
+	valid.Register(func() testServiceA { return newTestServiceA("a", nil) })
+	VerifyFrom(t, valid)
+
+	t.Run("inject fields", func(t *testing.T) {
+		ctn := New(nil)
+		InjectedTo[*testValidateInjected](ctn)
+
+		err := ctn.Validate()
+		require.ErrorIs(t, err, ErrMissingDependency)
+		require.Contains(t, err.Error(), "missing dependency di.testServiceA")
+
+		ctn.Register(func() testServiceA { return newTestServiceA("a", nil) })
+		VerifyFrom(t, ctn)
+	})
+
+	t.Run("strict mode after validate", func(t *testing.T) {
+		ctn := New(nil)
+		ctn.Register(func(s testServiceA) *testResolveRoot { return &testResolveRoot{} })
+		ctn.Register(func() *testServiceAImpl { return newTestServiceA("a", nil).(*testServiceAImpl) })
+		VerifyFrom(t, ctn)
+
+		require.NoError(t, ctn.Configure(WithStrictMode()))
+		require.NoError(t, ctn.Initialize())
+		_, err := GetFrom[*testResolveRoot](ctn)
+		require.ErrorIs(t, err, ErrMissingDependency)
+	})
+}
+
+type testValidateInjected struct {
+	Service testServiceA     `inject:""`
+	Leaf    *testResolveLeaf `inject:""` // injected automatically
+	Ctx     context.Context  `inject:""`
+}
+
+func TestValidateScopeViolation(t *testing.T) {
+	ctn := New(nil)
+
+	ctn.Register(func() testServiceA { return newTestServiceA("a", nil) }, Prototype)
+	ctn.Register(func(s testServiceA) *testResolveRoot { return &testResolveRoot{} })
+
+	err := ctn.Validate()
+	require.ErrorIs(t, err, ErrScopeViolation)
+
+	var scopeErr *ScopeViolationError
+	require.ErrorAs(t, err, &scopeErr)
+	require.Equal(t, Key[testServiceA](), scopeErr.Key)
+	require.Contains(t, err.Error(), "di.Provider[di.testServiceA]")
+
+	// providers and startup functions are allowed
+	valid := New(nil)
+	valid.Register(func() testServiceA { return newTestServiceA("a", nil) }, Prototype)
+	valid.Register(func(s Provider[testServiceA]) *testResolveRoot { return &testResolveRoot{} })
+	valid.Register(func(s testServiceA) {}, Startup(100))
+	VerifyFrom(t, valid)
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	ctn := New(nil,
+		WithLogger(logger),
+		WithLogLevel(LogCreation, slog.LevelInfo),
+		WithLogLevel(LogDisposal, slog.LevelInfo),
+	)
+
+	ctn.Register(func() *testServiceAImpl {
+		return newTestServiceA("a", nil).(*testServiceAImpl)
+	})
+	ctn.Register(func(u Unmanaged[testServiceA]) {
+		_, disposer, _ := u.Get()
+		disposer.Dispose()
+	}, Startup(100))
+
+	require.NoError(t, ctn.Initialize())
+
+	logs := buf.String()
+	require.NotContains(t, logs, "component registered")
+	require.NotContains(t, logs, "is a candidate for")
+	require.Contains(t, logs, `msg="[di] component created" key=*di.testServiceAImpl scope=singleton duration=`)
+	require.Contains(t, logs, `msg="[di] component disposed" key=*di.testServiceAImpl`)
+
+	_, err := GetFrom[*testResolveRoot](ctn)
+	require.Error(t, err)
+	require.Contains(t, buf.String(), `level=ERROR msg="[di] cannot resolve component" key=*di.testResolveRoot`)
+
+	t.Run("child", func(t *testing.T) {
+		buf.Reset()
+		child := ctn.NewChild()
+		require.NoError(t, child.Initialize())
+
+		_, err := GetFrom[*testResolveRoot](child)
+		require.Error(t, err)
+		require.Equal(t, 1, strings.Count(buf.String(), "cannot resolve component"))
+	})
+}
+
+func TestContainerConfig(t *testing.T) {
+	t.Run("strict mode", func(t *testing.T) {
+		ctn := New(nil, WithStrictMode())
+		ctn.Register(func() *testServiceAImpl { return newTestServiceA("a", nil).(*testServiceAImpl) })
+		require.NoError(t, ctn.Initialize())
+
+		_, err := GetFrom[testServiceA](ctn)
+		require.ErrorIs(t, err, ErrCandidateNotFound)
+
+		_, err = GetFrom[*testServiceAImpl](ctn)
+		require.NoError(t, err)
+	})
+
+	t.Run("default scope", func(t *testing.T) {
+		ctn := New(nil)
+		ctn.Register(func() testServiceA { return newTestServiceA("a", nil) })
+		ctn.Register(func() testServiceB { return newTestServiceB("b", nil) }, Singleton)
+
+		require.NoError(t, ctn.Configure(WithDefaultScope(SCOPE_PROTOTYPE)))
+		require.NoError(t, ctn.Initialize())
+		require.ErrorIs(t, ctn.Configure(WithStrictMode()), ErrContainerLocked)
+
+		a1, _ := GetFrom[testServiceA](ctn)
+		a2, _ := GetFrom[testServiceA](ctn)
+		require.True(t, a1 != a2, "expected prototype")
+
+		b1, _ := GetFrom[testServiceB](ctn)
+		b2, _ := GetFrom[testServiceB](ctn)
+		require.True(t, b1 == b2, "expected singleton")
+	})
+
+	t.Run("default scope startup", func(t *testing.T) {
+		var calls []string
+		ctn := New(nil, WithDefaultScope(SCOPE_PROTOTYPE))
+		ctn.Register(func() { calls = append(calls, "startup") }, Startup(0))
+		ctn.Register(func() *testResolveLeaf {
+			calls = append(calls, "eager")
+			return &testResolveLeaf{}
+		}, Eager)
+		require.NoError(t, ctn.Initialize())
+		require.ElementsMatch(t, []string{"startup", "eager"}, calls)
+
+		MustGetFrom[*testResolveLeaf](ctn)
+		require.Len(t, calls, 2, "expected singleton")
+	})
+
+	t.Run("lazy default", func(t *testing.T) {
+		var created []string
+		ctn := New(nil, WithLazyDefault(false))
+		ctn.Register(func() testServiceA { created = append(created, "a"); return nil })
+		ctn.Register(func() testServiceB { created = append(created, "b"); return nil }, Lazy)
+		ctn.Register(func() *testResolveRoot { created = append(created, "root"); return nil }, Prototype)
+		require.NoError(t, ctn.Initialize())
+		require.Equal(t, []string{"a"}, created)
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		ctn := New(nil, WithMaxDepth(2))
+		ctn.Register(func(m *testResolveMiddle) *testResolveRoot { return &testResolveRoot{} })
+		ctn.Register(func(l *testResolveLeaf) *testResolveMiddle { return &testResolveMiddle{} })
+		ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+		require.NoError(t, ctn.Initialize())
+
+		_, err := GetFrom[*testResolveRoot](ctn)
+		require.ErrorIs(t, err, ErrMaxDepthExceeded)
+
+		_, err = GetFrom[*testResolveMiddle](ctn)
+		require.NoError(t, err)
+	})
+
+	t.Run("mock", func(t *testing.T) {
+		ctn := New(nil, WithMock(false))
+		require.Panics(t, func() { ctn.Mock(&testServiceAImpl{}) })
+	})
+
+	t.Run("parent factories", func(t *testing.T) {
+		created := 0
+		parent := New(nil)
+		parent.Register(func() *testResolveLeaf { created++; return &testResolveLeaf{} })
+		parent.Register(func(l *testResolveLeaf) *testResolveMiddle { return &testResolveMiddle{} })
+		require.NoError(t, parent.Initialize())
+
+		child := New(parent, WithParentFactories())
+		require.NoError(t, child.Initialize())
+		require.True(t, child.Contains(Key[*testResolveMiddle]()))
+
+		MustGetFrom[*testResolveLeaf](parent)
+		MustGetFrom[*testResolveLeaf](child)
+		require.Equal(t, 2, created, "expected an instance per container")
+
+		MustGetFrom[*testResolveMiddle](child)
+		require.Equal(t, 2, created)
+	})
+}
+
+func TestMockFor(t *testing.T) {
+	ctn := New(nil)
+	ctn.Register(func() testServiceA { return newTestServiceA("a", nil) })
+	require.NoError(t, ctn.Initialize())
+
+	mock := newTestServiceA("mock", nil)
+	cleanup := MockFor[testServiceA](ctn, mock)
+	require.Same(t, mock, MustGetFrom[testServiceA](ctn))
+	cleanup()
+	require.NotSame(t, mock, MustGetFrom[testServiceA](ctn))
+
+	cleanup = MockFor[testServiceA](ctn, func() testServiceA { return mock })
+	require.Same(t, mock, MustGetFrom[testServiceA](ctn))
+	cleanup()
+
+	require.Panics(t, func() { MockFor[testServiceA](ctn, "invalid") })
+}
+
+func TestFactoryOf(t *testing.T) {
+	ctn := New(nil)
+	ctn.Register(func() testServiceA { return newTestServiceA("a", nil) })
+	ctn.Register(func() testServiceB { return newTestServiceB("b", nil) })
+	require.NoError(t, ctn.Initialize())
+
+	child := ctn.NewChild()
+	require.NoError(t, child.Initialize())
+
+	factory, err := FactoryOf[testServiceA](child)
+	require.NoError(t, err)
+	require.Equal(t, Key[testServiceA](), factory.Type())
+
+	_, err = FactoryOf[testServiceBase](child)
+	require.ErrorIs(t, err, ErrManyCandidates)
+
+	_, err = FactoryOf[*testResolveRoot](child)
+	require.ErrorIs(t, err, ErrCandidateNotFound)
+}
+
+func TestClone(t *testing.T) {
+	created := 0
+	ctn := New(nil, WithStrictMode())
+	ctn.Register(func() *testResolveLeaf { created++; return &testResolveLeaf{} }, Startup(0))
+	ctn.Register(func() testServiceA { return newTestServiceA("a", nil) })
+	require.NoError(t, ctn.Initialize())
+	require.Equal(t, 1, created)
+
+	clone := ctn.Clone()
+	clone.Register(func() *testServiceBImpl { return &testServiceBImpl{} })
+	require.NoError(t, clone.Initialize())
+	require.Equal(t, 2, created)
+	require.NotSame(t, MustGetFrom[testServiceA](ctn), MustGetFrom[testServiceA](clone))
+
+	// strict mode
+	_, err := GetFrom[testServiceB](clone)
+	require.ErrorIs(t, err, ErrCandidateNotFound)
+	require.False(t, ctn.Contains(Key[*testServiceBImpl]()))
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Cleanup(Snapshot())
+	Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+
+	original := Global()
+	restore := Snapshot()
+	require.NotSame(t, original, Global())
+	Register(func() *testResolveMiddle { return &testResolveMiddle{} })
+	require.NoError(t, Initialize())
+	_, err := Get[*testResolveLeaf]()
+	require.NoError(t, err)
+	restore()
+	require.Same(t, original, Global())
+
+	t.Cleanup(Snapshot())
+	require.NoError(t, Initialize())
+	_, err = Get[*testResolveMiddle]()
+	require.ErrorIs(t, err, ErrCandidateNotFound)
+}
+
+type testTenantDB struct{ name string }
+
+type testTenantRepository struct {
+	db   *testTenantDB
+	leaf *testResolveLeaf
+}
+
+func TestNewChild(t *testing.T) {
+	disposed := map[string]int{}
+	ctn := New(nil, WithStrictMode())
+	require.NoError(t, ctn.RegisterScope("request", &testRequestScope{}))
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ctn.Register(func() *testTenantDB { return &testTenantDB{name: "default"} })
+	ctn.Register(func(db *testTenantDB, leaf *testResolveLeaf) *testTenantRepository {
+		return &testTenantRepository{db: db, leaf: leaf}
+	}, Disposer[*testTenantRepository](func(r *testTenantRepository) { disposed[r.db.name]++ }))
+	ctn.Register(func(r *testTenantRepository) *testResolveRoot { return &testResolveRoot{} })
+	ctn.Register(func(l *testResolveLeaf) *testResolveMiddle { return &testResolveMiddle{} })
+	require.NoError(t, ctn.Initialize())
+	parentRepo := MustGetFrom[*testTenantRepository](ctn)
+
+	// registers while the parent is locked
+	tenant := ctn.NewChild()
+	tenant.Register(func() *testTenantDB { return &testTenantDB{name: "tenant"} })
+	require.NoError(t, tenant.Initialize())
+
+	repo := MustGetFrom[*testTenantRepository](tenant)
+	require.Equal(t, "tenant", repo.db.name)
+	require.NotSame(t, parentRepo, repo)
+	require.Same(t, MustGetFrom[*testResolveLeaf](ctn), repo.leaf, "shares the parent singletons")
+	require.Same(t, MustGetFrom[*testResolveMiddle](ctn), MustGetFrom[*testResolveMiddle](tenant))
+	require.True(t, tenant.Contains(Key[*testResolveRoot]()), "depends on the tenant db")
+	require.False(t, tenant.Contains(Key[*testResolveMiddle]()))
+	require.Equal(t, "default", MustGetFrom[*testTenantRepository](ctn).db.name)
+
+	// settings and scopes
+	child := tenant.(*container)
+	require.True(t, child.strict)
+	require.NotNil(t, child.scopes["request"])
+
+	require.NoError(t, tenant.Destroy())
+	require.Equal(t, map[string]int{"tenant": 1}, disposed)
+	require.NotNil(t, ctn.(*container).scopes["request"])
+	require.Same(t, parentRepo, MustGetFrom[*testTenantRepository](ctn))
+
+	t.Run("injected", func(t *testing.T) {
+		ctn := New(nil)
+		ctn.Register(func() *testTenantDB { return &testTenantDB{name: "default"} })
+		InjectedTo[*testTenantService](ctn)
+		require.NoError(t, ctn.Initialize())
+
+		tenant := ctn.NewChild()
+		tenant.Register(func() *testTenantDB { return &testTenantDB{name: "tenant"} })
+		require.NoError(t, tenant.Initialize())
+
+		require.Equal(t, "tenant", MustGetFrom[*testTenantService](tenant).DB.name)
+		require.Equal(t, "default", MustGetFrom[*testTenantService](ctn).DB.name)
+	})
+}
+
+type testTenantService struct {
+	DB *testTenantDB `inject:""`
+}
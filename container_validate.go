@@ -0,0 +1,144 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Validate checks the parameters (and the `inject` fields, see Injector) of all
+// registered factories for missing or
+// ambiguous candidates, private components of other modules (see Private), scope
+// names that were never registered and singletons that depend on shorter-lived
+// components (see ScopeViolationError). No constructor is invoked.
+//
+// Returns all problems found, joined (see errors.Join).
+func (c *container) Validate() error {
+	var factories []*Factory
+	for _, list := range c.factories {
+		factories = append(factories, list...)
+	}
+	sort.Slice(factories, func(i, j int) bool {
+		return factories[i].id < factories[j].id
+	})
+
+	getParam := c.GetParam
+	if !c.locked {
+		// the candidates are only updated by Initialize (see WithStrictMode), validate
+		// a copy of the parameters
+		params := make(map[reflect.Type]*Parameter)
+		getParam = func(key reflect.Type) *Parameter {
+			if p, exists := params[key]; exists {
+				return p
+			}
+			p := c.parseParam(key)
+			c.paramsMu.RLock()
+			c.refreshAlias(p)
+			c.paramsMu.RUnlock()
+			params[key] = p
+			return p
+		}
+	}
+
+	var errs []error
+	for _, f := range factories {
+		errs = append(errs, c.validateFactory(f, getParam)...)
+	}
+	return errors.Join(errs...)
+}
+
+func (c *container) validateFactory(f *Factory, getParam func(reflect.Type) *Parameter) (errs []error) {
+	if _, exists := c.scopes[f.scope]; !exists {
+		errs = append(errs, errors.Join(fmt.Errorf("%v: no scope registered for name %s", f, f.scope), ErrNoScopeNameRegistered))
+	}
+
+	ctx := c.beforeCreation(f.key, f, context.Background())
+
+	for _, param := range f.parameters {
+		if err := c.validateParam(f, getParam(param.Key()), getParam, ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, key := range f.injectKeys {
+		param := getParam(key)
+		if c.isMissing(param) && isInjectable(key) {
+			// injected automatically (see Injector)
+			continue
+		}
+		if err := c.validateParam(f, param, getParam, ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return
+}
+
+// validateParam checks a parameter (or `inject` field) of the factory
+func (c *container) validateParam(f *Factory, param *Parameter, getParam func(reflect.Type) *Parameter, ctx context.Context) error {
+	if c.isMissing(param) {
+		return c.missingDependencyError(f.key, f, []*Parameter{param}, context.Background())
+	}
+
+	key := resolveKeyOf(param)
+	if key == _keyContext || key == _keyContainer || c.isMocked(key) {
+		return nil
+	}
+
+	p := getParam(key)
+	if !p.HasCandidates() {
+		// provided by the parent
+		return nil
+	}
+
+	dep, err := c.resolveFactory(p, ctx)
+	if err != nil {
+		return err
+	}
+	if err = checkVisibility(key, dep, ctx); err != nil {
+		return err
+	}
+	if isCaptive(f, param, dep) {
+		return &ScopeViolationError{Factory: f, Dependency: dep, Key: key}
+	}
+	return nil
+}
+
+// isCaptive checks if a singleton depends directly on a shorter-lived (prototype
+// or custom scoped) component. Providers and scoped proxies resolve the instance
+// on demand, so they are allowed.
+func isCaptive(f *Factory, param *Parameter, dep *Factory) bool {
+	if !f.Singleton() || !f.ReturnsValue() || param.Provider() || dep.Mock() {
+		return false
+	}
+	if dep.proxyFor(resolveKeyOf(param)) {
+		return false
+	}
+	return !dep.Singleton()
+}
+
+// isMocked checks if exists a mock for the key (see Mock)
+func (c *container) isMocked(key reflect.Type) bool {
+	_, ok := c.getMock(key)
+	return ok
+}
+
+// getMock the mock registered for the key (see Mock)
+func (c *container) getMock(key reflect.Type) (mockFunc, bool) {
+	c.mockMu.RLock()
+	defer c.mockMu.RUnlock()
+	if !c.testingHasMock {
+		return nil, false
+	}
+	fn, ok := c.testingMocks[key]
+	return fn, ok
+}
+
+// resolveKeyOf the key used by ResolveArgs to resolve the parameter
+func resolveKeyOf(param *Parameter) reflect.Type {
+	if param.Provider() || param.Qualified() {
+		return param.Value()
+	}
+	return param.Key()
+}
@@ -0,0 +1,14 @@
+// Command dilint runs the dilint analyzer (see package dilint).
+//
+// Usage:
+//
+//	go install github.com/go-path/di/dilint/cmd/dilint@latest
+//	go vet -vettool=$(which dilint) ./...
+package main
+
+import (
+	"github.com/go-path/di/dilint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() { singlechecker.Main(dilint.Analyzer) }
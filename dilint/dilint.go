@@ -0,0 +1,384 @@
+// Package dilint defines an Analyzer that reports mistakes in the use of
+// github.com/go-path/di that would otherwise only show up at runtime (or never):
+//
+//   - `inject` tags on unexported fields, silently ignored by di.Injector
+//   - constructors registered with an invalid return shape (see di.ShouldRegister)
+//   - Qualified[T, Q] dependencies whose qualifier Q is never used in a Qualify[Q]
+//   - Get[T] calls on types that are never registered
+//
+// The last two checks need the whole program: they run on main packages, using the
+// registrations of all the packages they import.
+package dilint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const diPath = "github.com/go-path/di"
+
+var Analyzer = &analysis.Analyzer{
+	Name:      "dilint",
+	Doc:       "reports invalid registrations and injections of github.com/go-path/di",
+	URL:       "https://pkg.go.dev/github.com/go-path/di/dilint",
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	Run:       run,
+	FactTypes: []analysis.Fact{new(Registrations)},
+}
+
+// Registrations the use of the di package by a package, exported as a fact to
+// the packages that import it.
+type Registrations struct {
+	Provided   []string // types registered (Register, ShouldRegister, Injected...)
+	Qualifiers []string // Q of Qualify[Q]
+	Qualified  []Usage  // Qualified[T, Q] dependencies
+	Gets       []Usage  // Get[T] calls
+}
+
+// Usage a type used at a position
+type Usage struct {
+	Type      string
+	Qualifier string // Q of Qualified[T, Q]
+	Position  string
+	pos       token.Pos // only valid in the package being analyzed
+}
+
+func (*Registrations) AFact() {}
+
+func (r *Registrations) String() string {
+	return fmt.Sprintf("Registrations(provided=%d, qualifiers=%d, qualified=%d, gets=%d)",
+		len(r.Provided), len(r.Qualifiers), len(r.Qualified), len(r.Gets))
+}
+
+func (r *Registrations) empty() bool {
+	return len(r.Provided) == 0 && len(r.Qualifiers) == 0 && len(r.Qualified) == 0 && len(r.Gets) == 0
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	regs := &Registrations{}
+	usage := func(t types.Type, qualifier types.Type, pos token.Pos) Usage {
+		u := Usage{Type: typeString(t), Position: pass.Fset.Position(pos).String(), pos: pos}
+		if qualifier != nil {
+			u.Qualifier = typeString(qualifier)
+		}
+		return u
+	}
+
+	// Qualified[T, Q] dependency
+	qualifiedParam := func(expr ast.Expr) {
+		if t, q := qualifiedOf(pass.TypesInfo.TypeOf(expr)); t != nil {
+			regs.Qualified = append(regs.Qualified, usage(t, q, expr.Pos()))
+		}
+	}
+
+	nodes := []ast.Node{(*ast.StructType)(nil), (*ast.FuncDecl)(nil), (*ast.FuncLit)(nil), (*ast.CallExpr)(nil)}
+	inspect.Preorder(nodes, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.StructType:
+			for _, field := range n.Fields.List {
+				if !hasInjectTag(field) {
+					continue
+				}
+				for _, name := range fieldNames(field) {
+					if !name.IsExported() {
+						pass.Reportf(name.Pos(), "inject tag on unexported field %s is ignored", name.Name)
+					}
+				}
+				qualifiedParam(field.Type)
+			}
+		case *ast.FuncDecl:
+			for _, param := range n.Type.Params.List {
+				qualifiedParam(param.Type)
+			}
+		case *ast.FuncLit:
+			for _, param := range n.Type.Params.List {
+				qualifiedParam(param.Type)
+			}
+		case *ast.CallExpr:
+			name, typeArgs := diFunc(pass.TypesInfo, n.Fun)
+			switch name {
+			case "Register", "ShouldRegister":
+				if len(n.Args) == 0 {
+					return
+				}
+				t := pass.TypesInfo.TypeOf(n.Args[0])
+				if t == nil {
+					return
+				}
+				if sig, isFunc := t.Underlying().(*types.Signature); isFunc {
+					valueIdx, ok := resultShape(sig)
+					if !ok {
+						pass.Reportf(n.Args[0].Pos(), "invalid constructor %s: expected results (), (T), (error), (T, error) or (error, T)",
+							types.TypeString(sig, types.RelativeTo(pass.Pkg)))
+					} else if valueIdx >= 0 {
+						regs.Provided = append(regs.Provided, typeString(sig.Results().At(valueIdx).Type()))
+					}
+				} else {
+					regs.Provided = append(regs.Provided, typeString(t))
+				}
+			case "Injector", "Injected", "InjectedTo":
+				if typeArgs != nil && typeArgs.Len() > 0 {
+					regs.Provided = append(regs.Provided, typeString(typeArgs.At(0)))
+				}
+			case "Qualify":
+				if typeArgs != nil && typeArgs.Len() > 0 {
+					regs.Qualifiers = append(regs.Qualifiers, typeString(typeArgs.At(0)))
+				}
+			case "Get", "GetFrom", "MustGetFrom":
+				if typeArgs != nil && typeArgs.Len() > 0 {
+					regs.Gets = append(regs.Gets, usage(typeArgs.At(0), nil, n.Pos()))
+				}
+			}
+		}
+	})
+
+	if pass.Pkg.Name() == "main" {
+		checkProgram(pass, regs)
+	} else if !regs.empty() {
+		pass.ExportPackageFact(regs)
+	}
+	return nil, nil
+}
+
+// checkProgram the checks that need all the registrations of the program
+func checkProgram(pass *analysis.Pass, own *Registrations) {
+	all := []*Registrations{own}
+	for _, fact := range pass.AllPackageFacts() {
+		if regs, ok := fact.Fact.(*Registrations); ok && fact.Package != pass.Pkg {
+			all = append(all, regs)
+		}
+	}
+
+	qualifiers := map[string]bool{}
+	provided := map[string]bool{}
+	for _, regs := range all {
+		for _, q := range regs.Qualifiers {
+			qualifiers[q] = true
+		}
+		for _, p := range regs.Provided {
+			provided[p] = true
+		}
+	}
+	if len(provided) == 0 {
+		// the program does not register any component
+		return
+	}
+
+	lookup := newTypeLookup(pass.Pkg)
+	for i, regs := range all {
+		report := func(u Usage, format string, args ...any) {
+			if i == 0 {
+				pass.Reportf(u.pos, format, args...)
+			} else {
+				// dependency of the main package
+				pass.Reportf(pass.Files[0].Package, u.Position+": "+format, args...)
+			}
+		}
+
+		for _, u := range regs.Qualified {
+			if !qualifiers[u.Qualifier] {
+				report(u, "qualifier %s of Qualified[%s, %s] is never used in a Qualify[%s]", u.Qualifier, u.Type, u.Qualifier, u.Qualifier)
+			}
+		}
+		for _, u := range regs.Gets {
+			if !isProvided(lookup, provided, u.Type) {
+				report(u, "Get[%s]: %s is never registered", u.Type, u.Type)
+			}
+		}
+	}
+}
+
+// builtinKeys the keys resolved by the container without a registration (see di.Get)
+var builtinKeys = map[string]bool{
+	"context.Context":     true,
+	diPath + ".Container": true,
+}
+
+// isProvided checks if a registered type is assignable to the key. When a type
+// cannot be resolved, it is assumed to be provided (no false positives).
+func isProvided(lookup *typeLookup, provided map[string]bool, key string) bool {
+	if provided[key] || builtinKeys[key] {
+		return true
+	}
+	keyType := lookup.find(key)
+	if keyType == nil {
+		return true
+	}
+	if !types.IsInterface(keyType) {
+		return false
+	}
+
+	names := make([]string, 0, len(provided))
+	for p := range provided {
+		names = append(names, p)
+	}
+	sort.Strings(names)
+	for _, p := range names {
+		t := lookup.find(p)
+		if t == nil || types.AssignableTo(t, keyType) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeLookup finds the named types of the program by their string representation
+type typeLookup struct {
+	packages map[string]*types.Package
+}
+
+func newTypeLookup(main *types.Package) *typeLookup {
+	l := &typeLookup{packages: map[string]*types.Package{}}
+	var visit func(p *types.Package)
+	visit = func(p *types.Package) {
+		if l.packages[p.Path()] != nil {
+			return
+		}
+		l.packages[p.Path()] = p
+		for _, imported := range p.Imports() {
+			visit(imported)
+		}
+	}
+	visit(main)
+	return l
+}
+
+// find "path.Name" or "*path.Name", nil if not found
+func (l *typeLookup) find(s string) types.Type {
+	if strings.HasPrefix(s, "*") {
+		if elem := l.find(s[1:]); elem != nil {
+			return types.NewPointer(elem)
+		}
+		return nil
+	}
+	if strings.ContainsAny(s, "[]() ") {
+		return nil
+	}
+	dot := strings.LastIndex(s, ".")
+	if dot < 0 {
+		if obj := types.Universe.Lookup(s); obj != nil {
+			return obj.Type()
+		}
+		return nil
+	}
+	p := l.packages[s[:dot]]
+	if p == nil {
+		return nil
+	}
+	if obj, ok := p.Scope().Lookup(s[dot+1:]).(*types.TypeName); ok {
+		return obj.Type()
+	}
+	return nil
+}
+
+// diFunc the name and type arguments of a function (or Container method) of the di package
+func diFunc(info *types.Info, fun ast.Expr) (string, *types.TypeList) {
+	fun = ast.Unparen(fun)
+	switch e := fun.(type) {
+	case *ast.IndexExpr:
+		fun = e.X
+	case *ast.IndexListExpr:
+		fun = e.X
+	}
+
+	var ident *ast.Ident
+	switch e := ast.Unparen(fun).(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return "", nil
+	}
+
+	obj, ok := info.Uses[ident].(*types.Func)
+	if !ok || obj.Pkg() == nil || obj.Pkg().Path() != diPath {
+		return "", nil
+	}
+	return obj.Name(), info.Instances[ident].TypeArgs
+}
+
+// qualifiedOf the T and Q of a di.Qualified[T, Q] type
+func qualifiedOf(t types.Type) (types.Type, types.Type) {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != diPath || named.Obj().Name() != "Qualified" {
+		return nil, nil
+	}
+	if args := named.TypeArgs(); args != nil && args.Len() == 2 {
+		return args.At(0), args.At(1)
+	}
+	return nil, nil
+}
+
+// resultShape the index of the value result (-1 if none), same rules as di.ShouldRegister
+func resultShape(sig *types.Signature) (valueIdx int, ok bool) {
+	results := sig.Results()
+	switch results.Len() {
+	case 0:
+		return -1, true
+	case 1:
+		if isError(results.At(0).Type()) {
+			return -1, true
+		}
+		return 0, true
+	case 2:
+		a, b := isError(results.At(0).Type()), isError(results.At(1).Type())
+		if a && !b {
+			return 1, true
+		}
+		if !a && b {
+			return 0, true
+		}
+	}
+	return -1, false
+}
+
+func isError(t types.Type) bool {
+	return types.Implements(t, types.Universe.Lookup("error").Type().Underlying().(*types.Interface))
+}
+
+func hasInjectTag(field *ast.Field) bool {
+	if field.Tag == nil {
+		return false
+	}
+	tag, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return false
+	}
+	_, ok := reflect.StructTag(tag).Lookup("inject")
+	return ok
+}
+
+// fieldNames the names of the field, the type name for embedded fields
+func fieldNames(field *ast.Field) []*ast.Ident {
+	if len(field.Names) > 0 {
+		return field.Names
+	}
+	t := field.Type
+	if star, ok := t.(*ast.StarExpr); ok {
+		t = star.X
+	}
+	switch t := t.(type) {
+	case *ast.Ident:
+		return []*ast.Ident{t}
+	case *ast.SelectorExpr:
+		return []*ast.Ident{t.Sel}
+	}
+	return nil
+}
+
+func typeString(t types.Type) string {
+	return types.TypeString(t, nil)
+}
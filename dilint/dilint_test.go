@@ -0,0 +1,12 @@
+package dilint_test
+
+import (
+	"testing"
+
+	"github.com/go-path/di/dilint"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), dilint.Analyzer, "shapes", "app")
+}
@@ -0,0 +1,26 @@
+package main // want `lib.go:\d+:\d+: qualifier lib.Replica of Qualified\[\*lib.DB, lib.Replica\] is never used in a Qualify\[lib.Replica\]` `lib.go:\d+:\d+: Get\[lib.Missing\]: lib.Missing is never registered`
+
+import (
+	"context"
+
+	"github.com/go-path/di"
+	"lib"
+)
+
+type Local struct{}
+
+type Audit struct{}
+
+func main() {
+	di.Register(func(db di.Qualified[*lib.DB, Audit]) {}) // want `qualifier app.Audit of Qualified\[\*lib.DB, app.Audit\] is never used`
+
+	_, _ = di.Get[*lib.DB]()
+	_, _ = di.Get[lib.Store]()
+	_, _ = di.Get[*lib.Reports]()
+	_, _ = di.Get[*Local]() // want `Get\[\*app.Local\]: \*app.Local is never registered`
+	_, _ = di.Get[[]*lib.DB]()
+
+	// resolved without a registration
+	_, _ = di.Get[context.Context]()
+	_, _ = di.Get[di.Container]()
+}
@@ -0,0 +1,26 @@
+// Package di is a stub of github.com/go-path/di with the API used by dilint.
+package di
+
+import "context"
+
+type FactoryConfig func()
+
+type Container interface {
+	Register(ctor any, opts ...FactoryConfig)
+	ShouldRegister(ctor any, opts ...FactoryConfig) error
+}
+
+type Qualified[T any, Q any] struct{ value T }
+
+func (q Qualified[T, Q]) Get() T { return q.value }
+
+func Register(ctor any, opts ...FactoryConfig)                          {}
+func ShouldRegister(ctor any, opts ...FactoryConfig) error              { return nil }
+func Injected[T any](opts ...FactoryConfig)                             {}
+func InjectedTo[T any](c Container, opts ...FactoryConfig)              {}
+func Qualify[Q any]() FactoryConfig                                     { return nil }
+func Get[T any](ctx ...context.Context) (o T, e error)                  { return }
+func GetFrom[T any](c Container, ctx ...context.Context) (o T, e error) { return }
+func MustGetFrom[T any](c Container, ctx ...context.Context) T          { var t T; return t }
+
+func Injector[T any]() func(Container, context.Context) (T, error) { return nil }
@@ -0,0 +1,32 @@
+package lib
+
+import "github.com/go-path/di"
+
+type Primary struct{}
+
+type Replica struct{}
+
+type Store interface{ Load() string }
+
+type Memory struct{}
+
+func (*Memory) Load() string { return "" }
+
+type Missing interface{ Missing() }
+
+type Reports struct {
+	DB di.Qualified[*DB, Replica] `inject:""`
+}
+
+type DB struct{}
+
+func NewDB() *DB { return &DB{} }
+
+func init() {
+	di.Register(NewDB, di.Qualify[Primary]())
+	di.Register(func() *Memory { return &Memory{} })
+	di.Injected[*Reports]()
+
+	di.Register(func(db di.Qualified[*DB, Primary]) {})
+	_, _ = di.Get[Missing]()
+}
@@ -0,0 +1,52 @@
+package shapes // want package:`Registrations\(provided=4, qualifiers=0, qualified=0, gets=0\)`
+
+import (
+	"errors"
+
+	"github.com/go-path/di"
+)
+
+type Service struct {
+	Repo    *Repository `inject:""`
+	cache   *Cache      `inject:""` // want `inject tag on unexported field cache is ignored`
+	*Cache  `inject:""`
+	*config `inject:""` // want `inject tag on unexported field config is ignored`
+	plain   *Cache
+}
+
+type Repository struct{}
+
+type Cache struct{}
+
+type config struct{}
+
+type MyError struct{}
+
+func (MyError) Error() string { return "" }
+
+func NewRepository() (*Repository, error) { return &Repository{}, nil }
+
+func NewCache() (error, *Cache) { return nil, &Cache{} }
+
+func Start() error { return nil }
+
+func Pair() (*Repository, *Cache) { return nil, nil }
+
+func Errors() (error, *MyError) { return nil, nil }
+
+func Three() (*Repository, *Cache, error) { return nil, nil, nil }
+
+func init() {
+	di.Register(NewRepository)
+	di.Register(NewCache)
+	di.Register(Start)
+	di.Register(func() {})
+	di.Register(&Cache{})
+	di.Register(Pair)            // want `invalid constructor func\(\) \(\*Repository, \*Cache\)`
+	di.Register(Errors)          // want `invalid constructor func\(\) \(error, \*MyError\)`
+	_ = di.ShouldRegister(Three) // want `invalid constructor func\(\) \(\*Repository, \*Cache, error\)`
+
+	var c di.Container
+	c.Register(Pair) // want `invalid constructor`
+	c.Register(func() (*Cache, error) { return nil, errors.New("") })
+}
@@ -0,0 +1,47 @@
+// Package diotel adapts OpenTelemetry tracing to the di.Tracer hook.
+package diotel
+
+import (
+	"context"
+
+	"github.com/go-path/di"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New creates a di.Tracer that starts an OpenTelemetry span for each component
+// created by the container.
+//
+// Example:
+//
+//	ctn := di.New(nil, di.WithTracer(diotel.New(otel.Tracer("di"))))
+func New(tracer trace.Tracer) di.Tracer {
+	return &tracerImpl{tracer: tracer}
+}
+
+type tracerImpl struct {
+	tracer trace.Tracer
+}
+
+func (t *tracerImpl) Start(ctx context.Context, factory *di.Factory) (context.Context, di.Span) {
+	ctx, span := t.tracer.Start(ctx, "di.create "+factory.String(), trace.WithAttributes(
+		attribute.Int("di.factory.id", factory.Id()),
+		attribute.String("di.key", factory.Key().String()),
+		attribute.String("di.scope", factory.Scope()),
+		attribute.String("di.source", factory.Source()),
+	))
+	return ctx, &spanImpl{span: span}
+}
+
+type spanImpl struct {
+	span trace.Span
+}
+
+func (s *spanImpl) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
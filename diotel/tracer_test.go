@@ -0,0 +1,42 @@
+package diotel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-path/di"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type testRoot struct{}
+type testLeaf struct{}
+
+func TestTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	errFailed := errors.New("failed")
+
+	ctn := di.New(nil, di.WithTracer(New(provider.Tracer("di"))))
+	ctn.Register(func(l *testLeaf) *testRoot { return &testRoot{} })
+	ctn.Register(func() *testLeaf { return &testLeaf{} })
+	ctn.Register(func() error { return errFailed }, di.Startup(100))
+
+	require.ErrorIs(t, ctn.Initialize(), errFailed)
+
+	_, err := di.GetFrom[*testRoot](ctn)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+
+	leaf, root := spans[1], spans[2]
+	require.Equal(t, "di.create *diotel.testLeaf", leaf.Name())
+	require.Equal(t, "di.create *diotel.testRoot", root.Name())
+	require.Equal(t, root.SpanContext().SpanID(), leaf.Parent().SpanID())
+}
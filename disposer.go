@@ -2,6 +2,8 @@ package di
 
 import (
 	"context"
+	"log/slog"
+	"time"
 )
 
 type Callback func(any)
@@ -38,17 +40,19 @@ type DisposableAdapter interface {
 }
 
 type disposableAdapterImpl struct {
-	obj        any
-	factory    *Factory
-	container  Container
-	getContext func() context.Context
+	obj       any
+	factory   *Factory
+	container Container
+	ctx       context.Context
 }
 
 func (d *disposableAdapterImpl) Context() context.Context {
-	return d.getContext()
+	return d.ctx
 }
 
 func (d *disposableAdapterImpl) Dispose() {
+	start := time.Now()
+
 	if d, ok := d.obj.(Disposable); ok {
 		d.Destroy()
 	}
@@ -58,4 +62,17 @@ func (d *disposableAdapterImpl) Dispose() {
 			disposer(d.obj)
 		}
 	}
+
+	if c, ok := d.container.(*container); ok {
+		if c.logEnabled(LogDisposal) {
+			c.log(LogDisposal, "[di] component disposed",
+				slog.String("key", d.factory.String()),
+				slog.String("scope", d.factory.scope),
+				slog.Duration("duration", time.Since(start)),
+			)
+		}
+		if c.metrics != nil {
+			c.metrics.ComponentDisposed(d.factory, time.Since(start))
+		}
+	}
 }
@@ -0,0 +1,110 @@
+package ditest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock a fake di.Clock for the tests of scheduled components (see di.WithClock). The
+// time only moves with Advance.
+//
+// Example:
+//
+//	clock := ditest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	ctn := ditest.New(t, di.Global(), di.WithClock(clock))
+//	require.NoError(t, ctn.Initialize())
+//
+//	clock.BlockUntil(1)           // the task waits for its first run
+//	clock.Advance(30 * time.Second) // runs "@every 30s"
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*clockWaiter
+}
+
+type clockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewClock creates a fake clock at the given time
+func NewClock(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now the current time of the clock
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep waits until the clock is advanced by d (see Advance), returns false if the
+// context is canceled before
+func (c *Clock) Sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	c.mu.Lock()
+	w := &clockWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+	c.mu.Unlock()
+
+	select {
+	case <-w.ch:
+		return true
+	case <-ctx.Done():
+		c.remove(w)
+		return false
+	}
+}
+
+// remove the waiter, no longer waiting
+func (c *Clock) remove(w *clockWaiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, waiter := range c.waiters {
+		if waiter == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// Advance moves the clock forward, waking up the goroutines (see Sleep) that are due
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].at.Before(c.waiters[j].at)
+	})
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+		} else {
+			w.ch <- c.now
+		}
+	}
+	c.waiters = pending
+	c.cond.Broadcast()
+}
+
+// BlockUntil waits until n goroutines are waiting on the clock (see Sleep). Use before
+// Advance, to make sure the scheduled components are waiting for their next run.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
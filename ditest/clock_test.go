@@ -0,0 +1,118 @@
+package ditest_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-path/di"
+	"github.com/go-path/di/ditest"
+	"github.com/stretchr/testify/require"
+)
+
+type jobState struct{ id int32 }
+
+func TestClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := ditest.NewClock(start)
+
+	var runs, disposed atomic.Int32
+	done := make(chan int32, 10)
+
+	ctn := ditest.New(t, newBase(new(atomic.Int32)), di.WithClock(clock))
+	ctn.Register(func() *jobState { return &jobState{id: runs.Add(1)} }, di.Scoped(di.SCOPE_JOB),
+		di.Disposer[*jobState](func(*jobState) { disposed.Add(1) }))
+	ctn.Register(func(ctx context.Context, s *jobState, r Repository) error {
+		done <- s.id
+		if s.id == 3 {
+			return errors.New("failed")
+		}
+		return nil
+	}, di.Scheduled("@every 30s"))
+	require.NoError(t, ctn.Initialize())
+
+	clock.BlockUntil(1)
+	clock.Advance(29 * time.Second)
+	require.Empty(t, done)
+	clock.Advance(time.Second)
+	require.Equal(t, int32(1), <-done)
+
+	// a new job scope per run
+	clock.BlockUntil(1)
+	require.Equal(t, int32(1), disposed.Load())
+	clock.Advance(30 * time.Second)
+	require.Equal(t, int32(2), <-done)
+
+	// the runs missed are skipped
+	clock.BlockUntil(1)
+	clock.Advance(2 * time.Minute)
+	require.Equal(t, int32(3), <-done)
+	clock.BlockUntil(1)
+	require.Empty(t, done)
+	require.Equal(t, start.Add(3*time.Minute), clock.Now())
+
+	status := ctn.Workers()[0]
+	require.Equal(t, di.WorkerRunning, status.State)
+	require.ErrorContains(t, status.Err, "failed")
+
+	require.NoError(t, ctn.Destroy())
+	require.Equal(t, di.WorkerStopped, ctn.Workers()[0].State)
+}
+
+func TestClockSleepCanceled(t *testing.T) {
+	clock := ditest.NewClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	slept := make(chan bool)
+	go func() { slept <- clock.Sleep(ctx, time.Second) }()
+	clock.BlockUntil(1)
+	cancel()
+	require.False(t, <-slept)
+
+	// the canceled waiter is removed
+	blocked := make(chan struct{})
+	go func() {
+		clock.BlockUntil(1)
+		close(blocked)
+	}()
+	require.Never(t, func() bool {
+		select {
+		case <-blocked:
+			return true
+		default:
+			return false
+		}
+	}, 50*time.Millisecond, 5*time.Millisecond)
+
+	go func() { slept <- clock.Sleep(context.Background(), time.Second) }()
+	<-blocked
+	clock.Advance(time.Second)
+	require.True(t, <-slept)
+}
+
+func TestClockSkipMissed(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := ditest.NewClock(start)
+
+	done := make(chan time.Time, 10)
+	ctn := ditest.New(t, newBase(new(atomic.Int32)), di.WithClock(clock))
+	ctn.Register(func(ctx context.Context) {
+		done <- clock.Now()
+	}, di.Scheduled("@every 1s"))
+	require.NoError(t, ctn.Initialize())
+
+	clock.BlockUntil(1)
+	clock.Advance(24*time.Hour + 500*time.Millisecond)
+	require.Equal(t, start.Add(24*time.Hour+500*time.Millisecond), <-done)
+
+	// the next run keeps the interval of the schedule
+	clock.BlockUntil(1)
+	clock.Advance(499 * time.Millisecond)
+	require.Empty(t, done)
+	clock.Advance(time.Millisecond)
+	require.Equal(t, start.Add(24*time.Hour+time.Second), <-done)
+
+	require.NoError(t, ctn.Destroy())
+}
@@ -0,0 +1,58 @@
+// Package ditest provides isolated containers for tests.
+//
+// Example:
+//
+//	func TestService(t *testing.T) {
+//		t.Parallel()
+//
+//		ctn := ditest.New(t, di.Global())
+//		ditest.Override[Repository](t, ctn, &FakeRepository{})
+//		require.NoError(t, ctn.Initialize())
+//
+//		service := di.MustGetFrom[*Service](ctn)
+//		...
+//	}
+package ditest
+
+import (
+	"testing"
+
+	"github.com/go-path/di"
+)
+
+// New creates a container for the test with all the components registered in base (see
+// di.WithParentFactories), without their instances: each test creates its own
+// components, so parallel tests never share state and base is never changed. The
+// container is destroyed when the test ends.
+//
+// Initialize the container before use, until then components are resolved by base.
+func New(t testing.TB, base di.Container, configs ...di.ContainerConfig) di.Container {
+	t.Helper()
+
+	configs = append([]di.ContainerConfig{di.WithMock(true), di.WithParentFactories()}, configs...)
+	ctn := di.New(base, configs...)
+	t.Cleanup(func() {
+		ctn.Destroy()
+	})
+	return ctn
+}
+
+// Global replaces the global container by a copy until the end of the test (see
+// di.Snapshot), so the test can register, mock and initialize the components declared
+// by the packages of the application. Not for parallel tests, use New.
+func Global(t testing.TB) di.Container {
+	t.Helper()
+	t.Cleanup(di.Snapshot())
+	return di.Global()
+}
+
+// Override replaces the component T of the container (any key, interfaces included)
+// until the end of the test. Accepts a T, a "func() T", a "func(context.Context) T" or a
+// "func(context.Context) (T, error)".
+//
+// The components that depend on T receive the override, including the components
+// inherited from base (see New).
+func Override[T any](t testing.TB, ctn di.Container, override any) {
+	t.Helper()
+	t.Cleanup(di.MockFor[T](ctn, override))
+}
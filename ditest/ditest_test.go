@@ -0,0 +1,122 @@
+package ditest_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-path/di"
+	"github.com/go-path/di/ditest"
+	"github.com/stretchr/testify/require"
+)
+
+type Repository interface {
+	Find() string
+}
+
+type dbRepository struct{ conn string }
+
+func (*dbRepository) Find() string { return "db" }
+
+type fakeRepository struct{}
+
+func (*fakeRepository) Find() string { return "fake" }
+
+type Service struct {
+	repository Repository
+}
+
+func newBase(created *atomic.Int32) di.Container {
+	base := di.New(nil)
+	base.Register(func() Repository {
+		created.Add(1)
+		return &dbRepository{}
+	})
+	base.Register(func(r Repository) *Service { return &Service{repository: r} })
+	return base
+}
+
+func TestNew(t *testing.T) {
+	var created atomic.Int32
+	base := newBase(&created)
+	require.NoError(t, base.Initialize())
+
+	var services [2]*Service
+	t.Run("group", func(t *testing.T) {
+		for i := range services {
+			i := i
+			t.Run("parallel", func(t *testing.T) {
+				t.Parallel()
+				ctn := ditest.New(t, base)
+				require.NoError(t, ctn.Initialize())
+				services[i] = di.MustGetFrom[*Service](ctn)
+				require.Equal(t, "db", services[i].repository.Find())
+			})
+		}
+	})
+
+	require.NotSame(t, services[0], services[1])
+	require.NotSame(t, services[0].repository, services[1].repository)
+	require.Equal(t, int32(2), created.Load())
+}
+
+func TestOverride(t *testing.T) {
+	var created atomic.Int32
+	base := newBase(&created)
+
+	t.Run("value", func(t *testing.T) {
+		ctn := ditest.New(t, base)
+		ditest.Override[Repository](t, ctn, &fakeRepository{})
+		require.NoError(t, ctn.Initialize())
+
+		require.Equal(t, "fake", di.MustGetFrom[*Service](ctn).repository.Find())
+	})
+
+	t.Run("constructor", func(t *testing.T) {
+		ctn := ditest.New(t, base)
+		require.NoError(t, ctn.Initialize())
+		ditest.Override[Repository](t, ctn, func(ctx context.Context) Repository { return &fakeRepository{} })
+
+		require.Equal(t, "fake", di.MustGetFrom[*Service](ctn).repository.Find())
+	})
+
+	require.Equal(t, int32(0), created.Load())
+
+	// base is not changed
+	require.NoError(t, base.Initialize())
+	require.Equal(t, "db", di.MustGetFrom[*Service](base).repository.Find())
+}
+
+func TestCleanup(t *testing.T) {
+	disposed := 0
+	base := di.New(nil)
+	base.Register(func() *Service { return &Service{} }, di.Disposer[*Service](func(*Service) { disposed++ }))
+
+	t.Run("test", func(t *testing.T) {
+		ctn := ditest.New(t, base)
+		require.NoError(t, ctn.Initialize())
+		di.MustGetFrom[*Service](ctn)
+		ditest.Override[Repository](t, ctn, &fakeRepository{})
+	})
+
+	require.Equal(t, 1, disposed)
+	require.False(t, base.Contains(di.Key[Repository]()))
+}
+
+func TestGlobal(t *testing.T) {
+	original := di.Global()
+
+	t.Run("test", func(t *testing.T) {
+		ctn := ditest.Global(t)
+		require.NotSame(t, original, ctn)
+		di.Register(func() Repository { return &dbRepository{} })
+		ditest.Override[Repository](t, ctn, &fakeRepository{})
+		require.NoError(t, di.Initialize())
+
+		repository, err := di.Get[Repository]()
+		require.NoError(t, err)
+		require.Equal(t, "fake", repository.Find())
+	})
+
+	require.Same(t, original, di.Global())
+}
@@ -0,0 +1,172 @@
+package ditest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/go-path/di"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Call a method call recorded by a spy
+type Call struct {
+	Method  string
+	Args    []any
+	Results []any
+}
+
+// Recorder records the calls to a component (see Spy)
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Record records a call. Invoked by the spies generated by di-gen, not meant to be
+// called directly.
+func (r *Recorder) Record(method string, args []any, results []any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args, Results: results})
+}
+
+// Calls the recorded calls, in order. If methods are given, only the calls of these methods.
+func (r *Recorder) Calls(methods ...string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var calls []Call
+	for _, call := range r.calls {
+		if len(methods) == 0 || slices.Contains(methods, call.Method) {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// AssertCalled asserts that the method was called with the arguments. The arguments are
+// compared as in testify mocks: mock.Anything, mock.AnythingOfType and mock.MatchedBy
+// are accepted.
+func (r *Recorder) AssertCalled(t assert.TestingT, method string, args ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if r.called(method, args) {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("Expected %s to have been called with %v", method, args), r.String())
+}
+
+// AssertNotCalled asserts that the method was not called with the arguments (see AssertCalled)
+func (r *Recorder) AssertNotCalled(t assert.TestingT, method string, args ...any) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	if !r.called(method, args) {
+		return true
+	}
+	return assert.Fail(t, fmt.Sprintf("Expected %s not to have been called with %v", method, args), r.String())
+}
+
+// AssertNumberOfCalls asserts that the method was called n times
+func (r *Recorder) AssertNumberOfCalls(t assert.TestingT, method string, n int) bool {
+	if h, ok := t.(interface{ Helper() }); ok {
+		h.Helper()
+	}
+	return assert.Len(t, r.Calls(method), n, "calls of %s", method)
+}
+
+// String the recorded calls, one by line
+func (r *Recorder) String() string {
+	s := "calls:"
+	for _, call := range r.Calls() {
+		s += fmt.Sprintf("\n\t%s(%v) => %v", call.Method, call.Args, call.Results)
+	}
+	return s
+}
+
+func (r *Recorder) called(method string, args []any) bool {
+	for _, call := range r.Calls(method) {
+		if _, differences := mock.Arguments(args).Diff(call.Args); differences == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	spiesMu sync.RWMutex
+	spies   = map[reflect.Type]any{} // func(T, *Recorder) T by T
+)
+
+// RegisterSpy registers the constructor of the spy of the interface T, a proxy that
+// records the calls and forwards them to the target.
+//
+// Invoked by the code generated by di-gen -spies, not meant to be called directly.
+func RegisterSpy[T any](newSpy func(target T, recorder *Recorder) T) {
+	spiesMu.Lock()
+	defer spiesMu.Unlock()
+	spies[di.Key[T]()] = newSpy
+}
+
+// Spy replaces the component T of the container (an interface) by a spy of the real
+// component until the end of the test. The spy records the calls and forwards them to
+// the real component. The spies are generated by di-gen, no hand-written mock needed:
+//
+//	//go:generate go run github.com/go-path/di/cmd/di-gen -spies
+//
+// Example:
+//
+//	ctn := ditest.New(t, di.Global())
+//	require.NoError(t, ctn.Initialize())
+//	spy := ditest.Spy[Repository](t, ctn)
+//
+//	di.MustGetFrom[*Service](ctn).Save(ctx, user)
+//	spy.AssertCalled(t, "Save", mock.Anything, user)
+//
+// Components created before Spy keep the real component. The test fails when T is
+// missing or ambiguous in the container.
+func Spy[T any](t testing.TB, ctn di.Container) *Recorder {
+	t.Helper()
+
+	key := di.Key[T]()
+	spiesMu.RLock()
+	newSpy, ok := spies[key].(func(T, *Recorder) T)
+	spiesMu.RUnlock()
+	if !ok {
+		t.Fatalf("ditest: no spy for %v, generate it with di-gen -spies", key)
+		return nil
+	}
+
+	// the factory selected by the container, resolved before the spy replaces it
+	factory, err := di.FactoryOf[T](ctn)
+	if err != nil {
+		t.Fatalf("ditest: cannot spy %v: %v", key, err)
+		return nil
+	}
+
+	recorder := &Recorder{}
+	Override[T](t, ctn, func(ctx context.Context) (o T, err error) {
+		target, _, err := ctn.GetObjectFactory(factory, true, ctx)()
+		if err != nil || target == nil {
+			return
+		}
+		v, ok := target.(T)
+		if !ok {
+			return o, fmt.Errorf("ditest: the component %T is not a %v", target, key)
+		}
+		return newSpy(v, recorder), nil
+	})
+	return recorder
+}
@@ -0,0 +1,147 @@
+package ditest_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-path/di"
+	"github.com/go-path/di/ditest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type Notifier interface {
+	Notify(ctx context.Context, to string) error
+}
+
+type notifierImpl struct{ fail bool }
+
+func (n *notifierImpl) Notify(ctx context.Context, to string) error {
+	if n.fail {
+		return errors.New("offline")
+	}
+	return nil
+}
+
+// as generated by di-gen -spies
+type spyNotifier struct {
+	target   Notifier
+	recorder *ditest.Recorder
+}
+
+func (s spyNotifier) Notify(a0 context.Context, a1 string) error {
+	r0 := s.target.Notify(a0, a1)
+	s.recorder.Record("Notify", []any{a0, a1}, []any{r0})
+	return r0
+}
+
+func init() {
+	ditest.RegisterSpy(func(target Notifier, recorder *ditest.Recorder) Notifier {
+		return spyNotifier{target, recorder}
+	})
+}
+
+type failingT struct{ failed bool }
+
+func (f *failingT) Errorf(format string, args ...any) { f.failed = true }
+
+type Signup struct {
+	Notifier Notifier `inject:""`
+}
+
+func TestSpy(t *testing.T) {
+	base := di.New(nil)
+	base.Register(func() Notifier { return &notifierImpl{} })
+	di.InjectedTo[*Signup](base, di.Prototype)
+
+	ctn := ditest.New(t, base)
+	require.NoError(t, ctn.Initialize())
+	spy := ditest.Spy[Notifier](t, ctn)
+
+	signup := di.MustGetFrom[*Signup](ctn)
+	require.NoError(t, signup.Notifier.Notify(context.Background(), "bob"))
+	require.NoError(t, signup.Notifier.Notify(context.Background(), "alice"))
+
+	spy.AssertCalled(t, "Notify", mock.Anything, "bob")
+	spy.AssertNotCalled(t, "Notify", mock.Anything, "carol")
+	spy.AssertNumberOfCalls(t, "Notify", 2)
+	require.Equal(t, []any{nil}, spy.Calls("Notify")[1].Results)
+
+	failT := &failingT{}
+	require.False(t, spy.AssertCalled(failT, "Notify", mock.Anything, "carol"))
+	require.True(t, failT.failed)
+
+	spy.Reset()
+	require.Empty(t, spy.Calls())
+
+	// the real component is reused
+	require.Same(t, di.MustGetFrom[Notifier](ctn).(spyNotifier).target, signup.Notifier.(spyNotifier).target)
+}
+
+func TestSpyRealError(t *testing.T) {
+	base := di.New(nil)
+	base.Register(func() (Notifier, error) { return nil, errors.New("unavailable") })
+
+	ctn := ditest.New(t, base)
+	require.NoError(t, ctn.Initialize())
+	ditest.Spy[Notifier](t, ctn)
+
+	_, err := di.GetFrom[Notifier](ctn)
+	require.ErrorContains(t, err, "unavailable")
+}
+
+type fatalT struct {
+	*testing.T
+	fatal string
+}
+
+func (f *fatalT) Fatalf(format string, args ...any) { f.fatal = fmt.Sprintf(format, args...) }
+
+func TestSpyTarget(t *testing.T) {
+	t.Run("primary", func(t *testing.T) {
+		base := di.New(nil)
+		base.Register(func() *notifierImpl { return &notifierImpl{fail: true} })
+		base.Register(func() *notifierImpl { return &notifierImpl{} }, di.Primary)
+
+		ctn := ditest.New(t, base)
+		require.NoError(t, ctn.Initialize())
+		ditest.Spy[Notifier](t, ctn)
+
+		require.NoError(t, di.MustGetFrom[Notifier](ctn).Notify(context.Background(), "bob"))
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		base := di.New(nil)
+		base.Register(func() *notifierImpl { return &notifierImpl{} })
+		base.Register(func() *notifierImpl { return &notifierImpl{fail: true} })
+
+		ctn := ditest.New(t, base)
+		require.NoError(t, ctn.Initialize())
+		ft := &fatalT{T: t}
+		require.Nil(t, ditest.Spy[Notifier](ft, ctn))
+		require.Contains(t, ft.fatal, "multiple candidates")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		ctn := ditest.New(t, di.New(nil))
+		require.NoError(t, ctn.Initialize())
+		ft := &fatalT{T: t}
+		require.Nil(t, ditest.Spy[Notifier](ft, ctn))
+		require.Contains(t, ft.fatal, di.ErrCandidateNotFound.Error())
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		base := di.New(nil)
+		base.Register(func() Notifier { return nil })
+
+		ctn := ditest.New(t, base)
+		require.NoError(t, ctn.Initialize())
+		ditest.Spy[Notifier](t, ctn)
+
+		n, err := ctn.Get(di.Key[Notifier]())
+		require.NoError(t, err)
+		require.Nil(t, n)
+	})
+}
@@ -0,0 +1,213 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CycleError is returned when a dependency cycle is found, either during
+// registration (ErrCycleDetected) or during the creation of a component
+// (ErrCurrentlyInCreation).
+//
+// Example:
+//
+//	var cycleErr *di.CycleError
+//	if errors.As(err, &cycleErr) {
+//		print(cycleErr.Error()) // this component introduces a cycle: *A -> *B -> *A
+//	}
+type CycleError struct {
+	Path []*Factory // chain of factories, the last one repeats a previous factory
+	Err  error      // ErrCycleDetected or ErrCurrentlyInCreation
+}
+
+func (e *CycleError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+	b.WriteString(": ")
+
+	names := make([]string, len(e.Path))
+	for i, f := range e.Path {
+		names[i] = f.String()
+	}
+	b.WriteString(strings.Join(names, " -> "))
+
+	// constructors involved in the cycle (without the repeated one)
+	seen := map[*Factory]bool{}
+	for _, f := range e.Path {
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+		if source := f.Source(); source != "" {
+			b.WriteString("\n\t")
+			b.WriteString(f.String())
+			b.WriteString(": ")
+			b.WriteString(source)
+		}
+	}
+
+	return b.String()
+}
+
+func (e *CycleError) Unwrap() error {
+	return e.Err
+}
+
+// ResolutionErrorKind describes what failed while resolving a component
+type ResolutionErrorKind string
+
+const (
+	ResolutionNoCandidate    ResolutionErrorKind = "no_candidate"    // no factory provides the key
+	ResolutionManyCandidates ResolutionErrorKind = "many_candidates" // more than one factory provides the key
+	ResolutionFactoryError   ResolutionErrorKind = "factory_error"   // the constructor returned an error
+	ResolutionMaxDepth       ResolutionErrorKind = "max_depth"       // the resolution path is too deep (see WithMaxDepth)
+	ResolutionNotVisible     ResolutionErrorKind = "not_visible"     // the component is private to another module (see Private)
+	ResolutionCycle          ResolutionErrorKind = "cycle"           // a CycleError, only reported to Metrics
+	ResolutionOther          ResolutionErrorKind = "other"           // any other error, only reported to Metrics
+)
+
+// ResolutionStep is a step in the resolution path of a component
+type ResolutionStep struct {
+	Key     reflect.Type // the requested key
+	Factory *Factory     // the factory selected for the key, nil if none was selected
+}
+
+// String the key, or the constructor name for the funcs that return no value
+func (s ResolutionStep) String() string {
+	if s.Factory != nil && s.Key == _typeNilReturn {
+		return s.Factory.String()
+	}
+	return s.Key.String()
+}
+
+// ResolutionError is returned when a component cannot be resolved. It lists
+// the whole path from the requested component (root) to the failing one.
+//
+// Example:
+//
+//	var resErr *di.ResolutionError
+//	if errors.As(err, &resErr) {
+//		for _, step := range resErr.Path {
+//			print(step.Key.String())
+//		}
+//	}
+type ResolutionError struct {
+	Kind        ResolutionErrorKind
+	Key         reflect.Type     // the key that failed
+	Path        []ResolutionStep // from the requested root to the failing key
+	Candidates  []*Factory       // candidates found, when Kind = ResolutionManyCandidates
+	Suggestions []string         // "did you mean" suggestions, when Kind = ResolutionNoCandidate
+	Missing     []reflect.Type   // all the missing dependencies of the factory, when Err = ErrMissingDependency
+	Err         error            // underlying error (ErrCandidateNotFound, ErrManyCandidates, constructor error...)
+}
+
+func (e *ResolutionError) Error() string {
+	var b strings.Builder
+
+	root := e.Key.String()
+	if len(e.Path) > 0 {
+		root = e.Path[0].String()
+	}
+	fmt.Fprintf(&b, "cannot resolve %s: %s", root, e.reason())
+
+	if len(e.Path) > 1 {
+		for i, step := range e.Path {
+			b.WriteString("\n\t")
+			if i > 0 {
+				b.WriteString("-> ")
+			}
+			b.WriteString(step.String())
+			if step.Factory != nil {
+				if step.Factory.key != step.Key {
+					b.WriteString(" (" + step.Factory.String() + ")")
+				}
+				if source := step.Factory.Source(); source != "" {
+					b.WriteString(" " + source)
+				}
+			}
+			if i == len(e.Path)-1 {
+				b.WriteString(" <- " + strings.ReplaceAll(string(e.Kind), "_", " "))
+			}
+		}
+	}
+
+	for _, suggestion := range e.Suggestions {
+		b.WriteString("\n\tdid you mean " + suggestion + "?")
+	}
+
+	return b.String()
+}
+
+func (e *ResolutionError) reason() string {
+	switch e.Kind {
+	case ResolutionNoCandidate:
+		if e.Err == ErrMissingDependency {
+			if len(e.Missing) > 1 {
+				names := make([]string, len(e.Missing))
+				for i, key := range e.Missing {
+					names[i] = key.String()
+				}
+				return "missing dependencies " + strings.Join(names, ", ")
+			}
+			return fmt.Sprintf("missing dependency %v", e.Key)
+		}
+		return fmt.Sprintf("no candidate found for %v", e.Key)
+	case ResolutionManyCandidates:
+		names := make([]string, len(e.Candidates))
+		for i, f := range e.Candidates {
+			names[i] = f.String()
+			if source := f.Source(); source != "" {
+				names[i] += " " + source
+			}
+		}
+		return fmt.Sprintf("multiple candidates for %v: %s", e.Key, strings.Join(names, ", "))
+	case ResolutionFactoryError:
+		key := e.Key.String()
+		if len(e.Path) > 0 {
+			key = e.Path[len(e.Path)-1].String()
+		}
+		return fmt.Sprintf("%s constructor returned an error: %v", key, e.Err)
+	case ResolutionMaxDepth:
+		return fmt.Sprintf("%v exceeds the max resolution depth (%d)", e.Key, len(e.Path)-1)
+	case ResolutionNotVisible:
+		factory := e.Path[len(e.Path)-1].Factory
+		if len(e.Path) > 1 && e.Path[len(e.Path)-2].Factory != nil {
+			consumer := e.Path[len(e.Path)-2].Factory
+			return fmt.Sprintf("%v is private to module %q, not visible to %v (module %q)", e.Key, factory.module, consumer, consumer.module)
+		}
+		return fmt.Sprintf("%v is private to module %q", e.Key, factory.module)
+	}
+	return e.Err.Error()
+}
+
+func (e *ResolutionError) Unwrap() error {
+	return e.Err
+}
+
+// ScopeViolationError is returned by Validate when a singleton depends directly on
+// a component with a shorter lifecycle (captive dependency). The singleton would
+// capture a single instance of the dependency forever.
+type ScopeViolationError struct {
+	Factory    *Factory     // the singleton component
+	Dependency *Factory     // the captured component
+	Key        reflect.Type // the parameter key
+}
+
+func (e *ScopeViolationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: singleton %v depends on %s component %v, a single instance would be captured forever",
+		ErrScopeViolation, e.Factory, e.Dependency.scope, e.Dependency)
+	if source := e.Factory.Source(); source != "" {
+		b.WriteString("\n\t" + e.Factory.String() + ": " + source)
+	}
+	fmt.Fprintf(&b, "\n\tinject di.Provider[%v] and call Get(), to resolve the instance when it is needed", e.Key)
+	if e.Key.Kind() == reflect.Interface {
+		fmt.Fprintf(&b, "\n\tor register %v with di.ScopedProxy, to inject a proxy that resolves the instance on each call", e.Dependency)
+	}
+	return b.String()
+}
+
+func (e *ScopeViolationError) Unwrap() error {
+	return ErrScopeViolation
+}
@@ -0,0 +1,200 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+)
+
+// Listener a component notified of the events of type E published in the container
+// (see Container.Publish). Events are dispatched to the listeners whose E is assignable
+// from the type of the event, ordered by Order.
+//
+// Example:
+//
+//	type UserCreated struct{ Id string }
+//
+//	type WelcomeMailer struct{}
+//
+//	func (m *WelcomeMailer) OnEvent(ctx context.Context, event UserCreated) error {
+//		return m.send(ctx, event.Id)
+//	}
+//
+//	di.Register(&WelcomeMailer{}, di.Order(10))
+type Listener[E any] interface {
+	OnEvent(ctx context.Context, event E) error
+}
+
+// ContainerInitialized published at the end of Container.Initialize, after the startup
+// components are created. A listener error fails the initialization.
+type ContainerInitialized struct {
+	Container Container
+}
+
+// ComponentCreated published after each instance is created (and initialized). Listener
+// errors are logged. The listeners being created (and their dependencies) are not notified.
+type ComponentCreated struct {
+	Factory  *Factory
+	Instance any
+}
+
+// ShutdownStarted published at the beginning of Container.Destroy. Listener errors are logged.
+type ShutdownStarted struct {
+	Container Container
+}
+
+// Async indicates that the listener is notified in a new goroutine, Publish does not wait
+// for it. Errors are logged. Destroy waits for the running notifications (see
+// WithShutdownTimeout), the events published after ShutdownStarted are not dispatched
+// to async listeners.
+//
+// Example:
+//
+//	di.Listen(func(ctx context.Context, e UserCreated) error {
+//		return audit.Log(ctx, e)
+//	}, di.Async)
+func Async(f *Factory) {
+	f.async = true
+}
+
+// ListenTo registers a function listener of the events of type E (see Listener).
+//
+// Example:
+//
+//	di.ListenTo(ctn, func(ctx context.Context, e UserCreated) error {
+//		return nil
+//	}, di.Order(1))
+func ListenTo[E any](c Container, listener func(context.Context, E) error, opts ...FactoryConfig) {
+	c.Register(&funcListener[E]{fn: listener}, opts...)
+}
+
+// funcListener a function registered as a Listener (see ListenTo)
+type funcListener[E any] struct {
+	fn func(context.Context, E) error
+}
+
+func (l *funcListener[E]) OnEvent(ctx context.Context, event E) error {
+	return l.fn(ctx, event)
+}
+
+// Publish dispatches the event to the listeners of this container (see Listener), stops
+// at the first error of a synchronous listener.
+func (c *container) Publish(ctx context.Context, event any) error {
+	if event == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	path := getCreationPath(ctx)
+	for _, factory := range c.listenersOf(reflect.TypeOf(event)) {
+		if path.contains(factory.Id()) {
+			// being created
+			continue
+		}
+
+		if factory.async {
+			c.asyncMu.Lock()
+			if c.asyncStopped {
+				// Destroy started
+				c.asyncMu.Unlock()
+				continue
+			}
+			c.asyncWg.Add(1)
+			c.asyncMu.Unlock()
+
+			go func(factory *Factory) {
+				defer c.asyncWg.Done()
+				if err := c.notify(context.WithoutCancel(ctx), factory, event); err != nil {
+					c.log(LogError, "[di] event listener failed", slog.String("listener", factory.String()), slog.Any("error", err))
+				}
+			}(factory)
+			continue
+		}
+
+		if err := c.notify(ctx, factory, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// publishLifecycle publishes a lifecycle event, the errors are logged
+func (c *container) publishLifecycle(ctx context.Context, event any) {
+	if err := c.Publish(ctx, event); err != nil {
+		c.log(LogError, "[di] event listener failed", slog.String("event", reflect.TypeOf(event).String()), slog.Any("error", err))
+	}
+}
+
+// notify calls the OnEvent method of the listener
+func (c *container) notify(ctx context.Context, factory *Factory, event any) error {
+	listener, _, err := c.GetObjectFactory(factory, true, ctx)()
+	if err != nil {
+		return err
+	}
+
+	out := reflect.ValueOf(listener).MethodByName("OnEvent").Call([]reflect.Value{reflect.ValueOf(ctx), reflect.ValueOf(event)})
+	if err, _ := out[0].Interface().(error); err != nil {
+		return fmt.Errorf("%v: %w", factory, err)
+	}
+	return nil
+}
+
+// listenersOf the listeners of the event type, by Order (and registration). Cached after
+// the container is locked.
+func (c *container) listenersOf(eventType reflect.Type) []*Factory {
+	if c.locked {
+		c.plansMu.RLock()
+		listeners, exists := c.listenerPlans[eventType]
+		c.plansMu.RUnlock()
+		if exists {
+			return listeners
+		}
+	}
+
+	var listeners []*Factory
+	for _, factories := range c.factories {
+		for _, f := range factories {
+			if !f.ReturnsValue() {
+				continue
+			}
+			if listenerEvent, ok := listenerEventOf(f.Type()); ok && eventType.AssignableTo(listenerEvent) {
+				listeners = append(listeners, f)
+			}
+		}
+	}
+	sort.Slice(listeners, func(i, j int) bool {
+		if listeners[i].order != listeners[j].order {
+			return listeners[i].order < listeners[j].order
+		}
+		return listeners[i].id < listeners[j].id
+	})
+
+	if c.locked {
+		c.plansMu.Lock()
+		c.listenerPlans[eventType] = listeners
+		c.plansMu.Unlock()
+	}
+	return listeners
+}
+
+// listenerEventOf the event type of a Listener (E of the OnEvent method)
+func listenerEventOf(t reflect.Type) (reflect.Type, bool) {
+	method, ok := t.MethodByName("OnEvent")
+	if !ok {
+		return nil, false
+	}
+
+	mt := method.Type
+	in := 1 // receiver
+	if t.Kind() == reflect.Interface {
+		in = 0
+	}
+	if mt.NumIn() != in+2 || mt.In(in) != _keyContext || mt.NumOut() != 1 || mt.Out(0) != _typeErr {
+		return nil, false
+	}
+	return mt.In(in + 1), true
+}
@@ -0,0 +1,169 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testUserCreated struct{ id string }
+
+type testDomainEvent interface{ domain() }
+
+func (testUserCreated) domain() {}
+
+type testMailer struct {
+	leaf *testResolveLeaf
+	sent []string
+}
+
+func (m *testMailer) OnEvent(ctx context.Context, event testUserCreated) error {
+	m.sent = append(m.sent, event.id)
+	return nil
+}
+
+func TestPublish(t *testing.T) {
+	ctn := New(nil)
+	var calls []string
+	ctn.Register(func(leaf *testResolveLeaf) *testMailer { return &testMailer{leaf: leaf} }, Order(2))
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ListenTo(ctn, func(ctx context.Context, e testUserCreated) error {
+		calls = append(calls, "first "+e.id)
+		return nil
+	}, Order(1))
+	ListenTo(ctn, func(ctx context.Context, e testDomainEvent) error {
+		calls = append(calls, "domain")
+		return nil
+	}, Order(3))
+	ListenTo(ctn, func(ctx context.Context, e string) error {
+		calls = append(calls, "string")
+		return nil
+	})
+	require.NoError(t, ctn.Initialize())
+
+	require.NoError(t, ctn.Publish(context.Background(), testUserCreated{id: "1"}))
+	require.Equal(t, []string{"first 1", "domain"}, calls)
+	require.Equal(t, []string{"1"}, MustGetFrom[*testMailer](ctn).sent)
+
+	t.Run("error", func(t *testing.T) {
+		ctn := New(nil)
+		ListenTo(ctn, func(ctx context.Context, e testUserCreated) error {
+			return errors.New("failed")
+		})
+		ListenTo(ctn, func(ctx context.Context, e testUserCreated) error {
+			t.Fatal("not called after an error")
+			return nil
+		})
+		require.NoError(t, ctn.Initialize())
+		require.ErrorContains(t, ctn.Publish(context.Background(), testUserCreated{}), "failed")
+	})
+
+	t.Run("async", func(t *testing.T) {
+		ctn := New(nil)
+		done := make(chan string, 1)
+		ListenTo(ctn, func(ctx context.Context, e testUserCreated) error {
+			done <- e.id
+			return errors.New("logged")
+		}, Async)
+		require.NoError(t, ctn.Initialize())
+		require.NoError(t, ctn.Publish(context.Background(), testUserCreated{id: "async"}))
+		require.Equal(t, "async", <-done)
+	})
+
+	t.Run("async shutdown", func(t *testing.T) {
+		ctn := New(nil)
+		ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+		release := make(chan struct{})
+		var leaf *testResolveLeaf
+		ListenTo(ctn, func(ctx context.Context, e ShutdownStarted) error {
+			<-release
+			leaf = MustGetFrom[*testResolveLeaf](ctn)
+			return nil
+		}, Async)
+		notified := false
+		ListenTo(ctn, func(ctx context.Context, e testUserCreated) error {
+			notified = true
+			return nil
+		}, Async)
+		require.NoError(t, ctn.Initialize())
+
+		close(release)
+		require.NoError(t, ctn.Destroy(), "waits for the async listeners")
+		require.NotNil(t, leaf)
+
+		require.NoError(t, ctn.(*container).Publish(context.Background(), testUserCreated{}))
+		require.False(t, notified, "not dispatched after the shutdown")
+	})
+
+	t.Run("async shutdown timeout", func(t *testing.T) {
+		ctn := New(nil, WithShutdownTimeout(10*time.Millisecond))
+		release := make(chan struct{})
+		defer close(release)
+		ListenTo(ctn, func(ctx context.Context, e ShutdownStarted) error {
+			<-release
+			return nil
+		}, Async)
+		require.NoError(t, ctn.Initialize())
+		require.ErrorIs(t, ctn.Destroy(), ErrShutdownTimeout)
+	})
+}
+
+type testCreatedRecorder struct {
+	leaf    *testResolveLeaf
+	created []string
+}
+
+func (r *testCreatedRecorder) OnEvent(ctx context.Context, e ComponentCreated) error {
+	r.created = append(r.created, e.Factory.String())
+	return nil
+}
+
+func TestLifecycleEvents(t *testing.T) {
+	var events []string
+	ctn := New(nil)
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} }, Startup(0))
+	ListenTo(ctn, func(ctx context.Context, e ComponentCreated) error {
+		events = append(events, "created "+e.Factory.String())
+		return nil
+	})
+	ListenTo(ctn, func(ctx context.Context, e ContainerInitialized) error {
+		require.Same(t, ctn, e.Container)
+		events = append(events, "initialized")
+		return nil
+	})
+	ListenTo(ctn, func(ctx context.Context, e ShutdownStarted) error {
+		events = append(events, "shutdown")
+		return nil
+	})
+
+	require.NoError(t, ctn.Initialize())
+	require.Equal(t, []string{"created *di.testResolveLeaf", "initialized"}, events)
+
+	require.NoError(t, ctn.Destroy())
+	require.Equal(t, []string{"created *di.testResolveLeaf", "initialized", "shutdown"}, events)
+
+	t.Run("listener dependencies", func(t *testing.T) {
+		ctn := New(nil)
+		ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+		ctn.Register(func(leaf *testResolveLeaf) *testCreatedRecorder {
+			return &testCreatedRecorder{leaf: leaf}
+		}, Startup(0))
+		ctn.Register(func() *testResolveMiddle { return &testResolveMiddle{} })
+		require.NoError(t, ctn.Initialize())
+
+		MustGetFrom[*testResolveMiddle](ctn)
+		// created before the listener
+		require.Equal(t, []string{"*di.testCreatedRecorder", "*di.testResolveMiddle"}, MustGetFrom[*testCreatedRecorder](ctn).created)
+	})
+
+	t.Run("initialization error", func(t *testing.T) {
+		ctn := New(nil)
+		ListenTo(ctn, func(ctx context.Context, e ContainerInitialized) error {
+			return errors.New("not ready")
+		})
+		require.ErrorContains(t, ctn.Initialize(), "not ready")
+	})
+}
@@ -3,13 +3,14 @@ package di
 import (
 	"context"
 	"reflect"
+	"time"
 )
 
 // nilReturn internal representation of a daemon/service factory
 // e. g. for a nil value returned from Factory
 type nilReturn struct{}
 
-type mockFunc func(ctx context.Context) any
+type mockFunc func(ctx context.Context) (any, error)
 
 type ConditionFunc func(Container, *Factory) bool
 
@@ -22,6 +23,9 @@ type Factory struct {
 	name           string                // human readable name
 	order          int                   // the order of this factory
 	scope          string                // Factory scope
+	scopeSet       bool                  // scope was explicitly configured
+	lazy           bool                  // lazy initialization (singletons)
+	lazySet        bool                  // lazy was explicitly configured (see Lazy and Eager)
 	startup        bool                  // will be initialized with container
 	isReference    bool                  // is a single reference (true singleton)
 	factoryType    reflect.Type          // type information about constructor
@@ -31,11 +35,21 @@ type Factory struct {
 	returnValueIdx int                   // value return index (0 or 1)
 	parameters     []*Parameter          // information about factory parameters.
 	parameterKeys  []reflect.Type        // type information about factory parameters.
+	injectKeys     []reflect.Type        // keys of the `inject` fields (see Injector)
 	initializers   []Callback            // post construct callbacks
 	disposers      []Callback            // disposal functions
 	conditions     []ConditionFunc       // indicates that a component is only eligible for registration when all specified conditions match.
 	qualifiers     map[reflect.Type]bool // component qualifiers
+	proxyKey       reflect.Type          // interface implemented by the proxy (see ScopedProxy)
+	proxy          func(supplier func(context.Context) (any, error)) any
 	mock           mockFunc
+	fake           reflect.Type   // component replaced by this fake (see FakeFor)
+	module         string         // see Module
+	private        bool           // only injectable in the same module (see Private)
+	async          bool           // listener notified in a new goroutine (see Async)
+	restart        *workerBackoff // see RestartOnError
+	schedule       *taskSchedule  // see Scheduled
+	jitter         time.Duration  // see Jitter
 }
 
 // Create a new instance of component.
@@ -61,6 +75,29 @@ func (f *Factory) Id() int {
 	return f.id
 }
 
+// String human readable identification of the component (key or constructor name)
+func (f *Factory) String() string {
+	if f.key == nil {
+		return "<nil>"
+	}
+	if f.key == _typeNilReturn {
+		if name := funcName(f.factoryValue); name != "" {
+			return name
+		}
+	}
+	return f.key.String()
+}
+
+// Source returns the constructor name and its source location
+// (ex. "pkg.NewService (/src/service.go:12)"). Returns an empty
+// string for components registered as instances.
+func (f *Factory) Source() string {
+	if f.isReference {
+		return ""
+	}
+	return funcSource(f.factoryValue)
+}
+
 // Key gets the factory component Key (Key = reflect.TypeOf(ComponentType))
 func (f *Factory) Key() reflect.Type {
 	return f.key
@@ -96,6 +133,12 @@ func (f *Factory) Startup() bool {
 	return f.startup
 }
 
+// Lazy returns true if this singleton is only created when requested. Non-lazy
+// singletons are created during the container initialization.
+func (f *Factory) Lazy() bool {
+	return f.lazy && !f.startup
+}
+
 // Order the order value of this factory
 //
 // Higher values are interpreted as lower priority. As a consequence,
@@ -187,7 +230,27 @@ func (f *Factory) HasQualifier(q reflect.Type) bool {
 	return false
 }
 
+// ScopedProxy returns true if this factory has a scoped proxy (see ScopedProxy)
+func (f *Factory) ScopedProxy() bool {
+	return f.proxy != nil
+}
+
 // Mock returns true if this is a Mock factory (testing)
 func (f *Factory) Mock() bool {
 	return f.mock != nil
 }
+
+// Fake returns true if this is the fake of a component (see FakeFor)
+func (f *Factory) Fake() bool {
+	return f.fake != nil
+}
+
+// Module the module of the component (see Module)
+func (f *Factory) Module() string {
+	return f.module
+}
+
+// Private returns true if the component is only injectable in its module (see Private)
+func (f *Factory) Private() bool {
+	return f.private
+}
@@ -140,7 +140,22 @@ func Condition(condition ConditionFunc) FactoryConfig {
 	}
 }
 
+// Lazy indicates that this singleton is only created when requested, even if the
+// container is configured with WithLazyDefault(false).
+func Lazy(f *Factory) {
+	f.lazy = true
+	f.lazySet = true
+}
+
+// Eager indicates that this singleton must be created during the container
+// initialization (Container.Initialize method)
+//
+// See Startup
+func Eager(f *Factory) {
+	f.lazy = false
+	f.lazySet = true
+}
+
 // @TODO:
-// Lazy, Whether lazy initialization should occur.
 // DependsOn[AnoterService]()
 // PreDestroy(T)
@@ -0,0 +1,138 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// FakeFor registers the component as the fake of T, an implementation for tests (Ex.
+// in memory, without network access). Fakes are ignored unless the container is
+// configured to use the fakes of a qualifier (see WithFakes).
+//
+// Example:
+//
+//	type ExternalAPI uint8
+//
+//	di.Register(NewStripeClient, di.Qualify[ExternalAPI]())
+//	di.Register(NewFakePaymentClient, di.FakeFor[PaymentClient]())
+func FakeFor[T any]() FactoryConfig {
+	key := Key[T]()
+	return func(f *Factory) {
+		f.fake = key
+	}
+}
+
+// WithFakes replaces every component qualified with Q by its fake (see FakeFor), the
+// fake registered for the component key or for an interface implemented by the
+// component. The replaced components are removed from the container, they are never
+// created. Initialize fails if a component qualified with Q has no fake.
+//
+// Example:
+//
+//	// integration tests, without network access
+//	ctn := ditest.New(t, di.Global(), di.WithFakes[ExternalAPI]())
+func WithFakes[Q any]() ContainerConfig {
+	qualifier := Key[Q]()
+	return func(c *container) {
+		if c.fakeQualifiers == nil {
+			c.fakeQualifiers = make(map[reflect.Type]bool)
+		}
+		c.fakeQualifiers[qualifier] = true
+	}
+}
+
+// addFake registers a fake, added to the container on Initialize (see applyFakes)
+func (c *container) addFake(fake *Factory) error {
+	if fake.returnType == _typeNilReturn || !fake.returnType.AssignableTo(fake.fake) {
+		return errors.Join(fmt.Errorf("%v is not a fake for %v", fake.factoryType, fake.fake), ErrInvalidProvider)
+	}
+	fake.key = fake.fake
+	fake.name = fake.key.String() + "_" + fake.factoryValue.String()
+	c.fakes = append(c.fakes, fake)
+	return nil
+}
+
+// applyFakes replaces the components qualified with the fake qualifiers by their fakes
+// (see WithFakes)
+func (c *container) applyFakes() error {
+	type replacement struct {
+		component *Factory
+		fake      *Factory
+	}
+	var replacements []replacement
+	var errs []error
+
+	// registration order. The container is only changed when every component has a fake
+	for _, f := range c.graph.nodes {
+		if f.Fake() || !c.registered(f) {
+			continue
+		}
+
+		var qualifiers []reflect.Type
+		for q := range c.fakeQualifiers {
+			if f.HasQualifier(q) {
+				qualifiers = append(qualifiers, q)
+			}
+		}
+		if len(qualifiers) == 0 {
+			continue
+		}
+
+		var fake *Factory
+		for _, candidate := range c.fakes {
+			if candidate.key == f.key || f.Type().AssignableTo(candidate.key) {
+				fake = candidate
+				break
+			}
+		}
+		if fake == nil {
+			errs = append(errs, fmt.Errorf("%v (qualified %v): %w", f, qualifiers[0], ErrNoFake))
+			continue
+		}
+		replacements = append(replacements, replacement{component: f, fake: fake})
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	applied := map[*Factory]bool{}
+	for _, r := range replacements {
+		c.removeFactory(r.component)
+		for q := range r.component.qualifiers {
+			r.fake.qualifiers[q] = true
+		}
+		applied[r.fake] = true
+	}
+
+	for _, fake := range c.fakes {
+		if applied[fake] {
+			if err := c.addFactory(fake); err != nil {
+				return err
+			}
+		}
+	}
+	c.fakes = nil
+	return nil
+}
+
+// registered checks if the factory is registered (see removeFactory)
+func (c *container) registered(f *Factory) bool {
+	for _, registered := range c.factories[f.key] {
+		if registered == f {
+			return true
+		}
+	}
+	return false
+}
+
+// removeFactory removes the factory from the container. The node is kept in the graph.
+func (c *container) removeFactory(f *Factory) {
+	factories := c.factories[f.key]
+	for i, registered := range factories {
+		if registered == f {
+			c.factories[f.key] = append(factories[:i:i], factories[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package di
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testExternalAPI uint8
+
+type testPaymentClient interface {
+	Charge(amount int) string
+}
+
+type testStripeClient struct{}
+
+func (*testStripeClient) Charge(amount int) string { return "stripe" }
+
+type testFakePaymentClient struct{}
+
+func (*testFakePaymentClient) Charge(amount int) string { return "fake" }
+
+type testCheckout struct {
+	client testPaymentClient
+}
+
+func registerTestPayment(ctn Container, created *[]string) {
+	ctn.Register(func() *testStripeClient {
+		*created = append(*created, "stripe")
+		return &testStripeClient{}
+	}, Qualify[testExternalAPI](), Startup(0))
+	ctn.Register(func() *testFakePaymentClient {
+		*created = append(*created, "fake")
+		return &testFakePaymentClient{}
+	}, FakeFor[testPaymentClient]())
+	ctn.Register(func(c testPaymentClient) *testCheckout { return &testCheckout{client: c} })
+}
+
+func TestFakes(t *testing.T) {
+	t.Run("ignored", func(t *testing.T) {
+		var created []string
+		ctn := New(nil)
+		registerTestPayment(ctn, &created)
+		require.NoError(t, ctn.Initialize())
+
+		require.Equal(t, "stripe", MustGetFrom[*testCheckout](ctn).client.Charge(10))
+		require.Equal(t, []string{"stripe"}, created)
+	})
+
+	t.Run("with fakes", func(t *testing.T) {
+		var created []string
+		ctn := New(nil, WithFakes[testExternalAPI]())
+		registerTestPayment(ctn, &created)
+		require.NoError(t, ctn.Initialize())
+
+		require.Equal(t, "fake", MustGetFrom[*testCheckout](ctn).client.Charge(10))
+		require.Equal(t, []string{"fake"}, created, "the real component is never created")
+		require.False(t, ctn.Contains(Key[*testStripeClient]()))
+
+		var qualified string
+		ctn2 := New(nil, WithFakes[testExternalAPI]())
+		registerTestPayment(ctn2, &created)
+		ctn2.Register(func(c Qualified[testPaymentClient, testExternalAPI]) { qualified = c.Get().Charge(10) }, Startup(1))
+		require.NoError(t, ctn2.Initialize())
+		require.Equal(t, "fake", qualified, "the fake carries the qualifiers of the component")
+	})
+
+	t.Run("child", func(t *testing.T) {
+		var created []string
+		parent := New(nil)
+		registerTestPayment(parent, &created)
+
+		ctn := New(parent, WithParentFactories(), WithFakes[testExternalAPI]())
+		require.NoError(t, ctn.Initialize())
+		require.Equal(t, "fake", MustGetFrom[*testCheckout](ctn).client.Charge(10))
+
+		require.NoError(t, parent.Initialize())
+		require.Equal(t, "stripe", MustGetFrom[*testCheckout](parent).client.Charge(10))
+	})
+
+	t.Run("no fake", func(t *testing.T) {
+		ctn := New(nil, WithFakes[testExternalAPI]())
+		ctn.Register(func() *testStripeClient { return &testStripeClient{} }, Qualify[testExternalAPI]())
+		require.ErrorIs(t, ctn.Initialize(), ErrNoFake)
+
+		// nothing is replaced
+		var created []string
+		ctn = New(nil, WithFakes[testExternalAPI]())
+		registerTestPayment(ctn, &created)
+		ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} }, Qualify[testExternalAPI]())
+		require.ErrorIs(t, ctn.Initialize(), ErrNoFake)
+		require.Len(t, ctn.(*container).factories[Key[*testStripeClient]()], 1)
+		require.Empty(t, ctn.(*container).factories[Key[testPaymentClient]()])
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		var created []string
+		ctn := New(nil, WithFakes[testExternalAPI]())
+		registerTestPayment(ctn, &created)
+		require.NoError(t, ctn.Initialize())
+
+		clone := ctn.Clone().(*container)
+		fake := ctn.(*container).factories[Key[testPaymentClient]()][0]
+		cloned := clone.factories[Key[testPaymentClient]()][0]
+		require.True(t, cloned.HasQualifier(Key[testExternalAPI]()))
+
+		cloned.qualifiers[Key[testQualifierA]()] = true
+		require.False(t, fake.HasQualifier(Key[testQualifierA]()), "the qualifiers are not shared")
+	})
+
+	t.Run("default scope", func(t *testing.T) {
+		var created []string
+		ctn := New(nil)
+		registerTestPayment(ctn, &created)
+		require.NoError(t, ctn.Configure(WithDefaultScope(SCOPE_PROTOTYPE), WithFakes[testExternalAPI]()))
+		require.NoError(t, ctn.Initialize())
+
+		MustGetFrom[testPaymentClient](ctn)
+		MustGetFrom[testPaymentClient](ctn)
+		require.Equal(t, []string{"fake", "fake"}, created, "expected prototype")
+	})
+
+	t.Run("invalid fake", func(t *testing.T) {
+		ctn := New(nil)
+		err := ctn.ShouldRegister(func() *testResolveLeaf { return nil }, FakeFor[testPaymentClient]())
+		require.ErrorIs(t, err, ErrInvalidProvider)
+	})
+}
+
+func TestDefaultFactorySortFake(t *testing.T) {
+	mock := &Factory{mock: func(ctx context.Context) (any, error) { return nil, nil }}
+	fake := &Factory{fake: Key[testPaymentClient](), qualifiers: map[reflect.Type]bool{}}
+	primary := &Factory{qualifiers: map[reflect.Type]bool{_primaryQualifierKey: true}}
+
+	require.True(t, DefaultFactorySortLessFn(mock, fake))
+	require.True(t, DefaultFactorySortLessFn(fake, primary))
+	require.False(t, DefaultFactorySortLessFn(primary, fake))
+}
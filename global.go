@@ -4,6 +4,7 @@ package di
 import (
 	"context"
 	"reflect"
+	"testing"
 )
 
 var global = New(nil)
@@ -12,11 +13,44 @@ func Global() Container {
 	return global
 }
 
+// Snapshot replaces the global container by a clone (see Container.Clone), with all the
+// components registered so far and none of its instances. restore brings back the
+// original global container. Test only, the global container is not synchronized: use
+// Container.Clone directly in parallel tests.
+//
+// Example:
+//
+//	func TestApp(t *testing.T) {
+//		t.Cleanup(di.Snapshot())
+//		di.Mock(&FakeRepository{})
+//		require.NoError(t, di.Initialize())
+//	}
+func Snapshot() (restore func()) {
+	original := global
+	global = original.Clone()
+	return func() {
+		global.Destroy()
+		global = original
+	}
+}
+
 // Initialize initialize all non-lazy singletons (startup)
 func Initialize(ctx ...context.Context) error {
 	return global.Initialize(ctx...)
 }
 
+// Configure reconfigure the global container. Must be called before Initialize
+//
+// Example:
+//
+//	func main() {
+//		di.Configure(di.WithLogger(logger), di.WithStrictMode())
+//		di.Initialize()
+//	}
+func Configure(configs ...ContainerConfig) error {
+	return global.Configure(configs...)
+}
+
 func Register(ctor any, opts ...FactoryConfig) {
 	global.Register(ctor, opts...)
 }
@@ -64,6 +98,29 @@ func Filter(options ...FactoryConfig) *FilteredFactories {
 	return global.Filter(options...)
 }
 
+// Graph returns a snapshot of the dependency graph (factories and their dependencies)
+func Graph() *DependencyGraph {
+	return global.Graph()
+}
+
+// Validate checks all factories for missing or ambiguous dependencies, unregistered
+// scopes and scope violations, without instantiating any component.
+func Validate() error {
+	return global.Validate()
+}
+
+// Verify fails the test if the global container has wiring errors (see Validate)
+//
+// Example:
+//
+//	func TestWiring(t *testing.T) {
+//		di.Verify(t)
+//	}
+func Verify(t testing.TB) {
+	t.Helper()
+	VerifyFrom(t, global)
+}
+
 func GetObjectFactory(factory *Factory, managed bool, ctx ...context.Context) CreateObjectFunc {
 	return global.GetObjectFactory(factory, managed, ctx...)
 }
@@ -92,6 +149,31 @@ func DestroySingletons() error {
 	return global.DestroySingletons()
 }
 
+// Listen registers a function listener of the events of type E (see Listener).
+//
+// Example:
+//
+//	di.Listen(func(ctx context.Context, e UserCreated) error {
+//		return nil
+//	})
+func Listen[E any](listener func(context.Context, E) error, opts ...FactoryConfig) {
+	ListenTo[E](global, listener, opts...)
+}
+
+// Publish dispatches the event to the listeners of the global container (see Listener)
+//
+// Example:
+//
+//	err := di.Publish(ctx, UserCreated{Id: id})
+func Publish(ctx context.Context, event any) error {
+	return global.Publish(ctx, event)
+}
+
+// Workers returns the status of the workers started by Initialize (see Worker)
+func Workers() []WorkerStatus {
+	return global.Workers()
+}
+
 // Mock test only, register a mock instance to the container
 func Mock(mock any) (cleanup func()) {
 	return global.Mock(mock)
@@ -20,6 +20,8 @@
 package di
 
 import (
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -163,3 +165,46 @@ func TestGraphIsCyclic(t *testing.T) {
 		assert.Equal(t, tt.cycle, cycle)
 	}
 }
+
+func TestContainerGraph(t *testing.T) {
+	ctn := New(nil)
+
+	ctn.Register(func() testServiceA {
+		return newTestServiceA("a", nil)
+	}, Qualify[testQualifierA]())
+
+	ctn.Register(func() *testServiceBImpl {
+		return newTestServiceB("b", nil).(*testServiceBImpl)
+	}, Prototype)
+
+	ctn.Register(func(a testServiceA, b testServiceB) {}, Startup(100))
+
+	g := ctn.Graph()
+
+	assert.Len(t, g.Nodes, 3)
+	assert.Equal(t, "di.testServiceA", g.Nodes[0].Key)
+	assert.Equal(t, []string{"di.testQualifierA"}, g.Nodes[0].Qualifiers)
+	assert.Equal(t, SCOPE_PROTOTYPE, g.Nodes[1].Scope)
+	assert.True(t, g.Nodes[2].Startup)
+
+	a, b, s := g.Nodes[0].Id, g.Nodes[1].Id, g.Nodes[2].Id
+	assert.Equal(t, []GraphEdge{
+		{From: s, To: a, Param: "di.testServiceA", Exact: true},
+		{From: s, To: b, Param: "di.testServiceB", Exact: false},
+	}, g.Edges)
+
+	dot := g.DOT()
+	assert.Contains(t, dot, fmt.Sprintf(`n%d -> n%d [label="di.testServiceA"];`, s, a))
+	assert.Contains(t, dot, fmt.Sprintf(`n%d -> n%d [label="di.testServiceB", style=dashed];`, s, b))
+
+	mermaid := g.Mermaid()
+	assert.Contains(t, mermaid, fmt.Sprintf(`n%d -- "di.testServiceA" --> n%d`, s, a))
+	assert.Contains(t, mermaid, fmt.Sprintf(`n%d -. "di.testServiceB" .-> n%d`, s, b))
+
+	data, err := g.JSON()
+	assert.NoError(t, err)
+
+	var decoded DependencyGraph
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, g.Edges, decoded.Edges)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"sync"
 )
 
 var ErrNotStruct = errors.New("the Injected method only accepts struct or *struct")
@@ -21,22 +22,36 @@ var ErrNotStruct = errors.New("the Injected method only accepts struct or *struc
 //
 // In the example above, the MyService dependency will be injected automatically.
 //
+// The injector generated by cmd/di-gen is used, if any (see RegisterInjector).
+//
 // @TODO:  embedded structs
-func Injector[T any]() func(Container, context.Context) (out T, err error) {
-	injector := InjectorOf(reflect.TypeOf((*T)(nil)).Elem())
+func Injector[T any]() InjectorFunc[T] {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+	InjectorOf(structType) // validates T
 	return func(ctn Container, ctx context.Context) (out T, err error) {
 		var o any
-		if o, err = injector(ctn, ctx); err == nil {
+		if o, err = InjectorOf(structType)(ctn, ctx); err == nil {
 			out = o.(T)
 		}
 		return
 	}
 }
 
+// InjectorFunc the constructor of T returned by Injector. The container resolves the
+// `inject` fields of T as dependencies of the component (see Validate and NewChild).
+type InjectorFunc[T any] func(Container, context.Context) (out T, err error)
+
+// injectKeys the keys of the `inject` fields of T
+func (InjectorFunc[T]) injectKeys() []reflect.Type {
+	_, keys := injectFields(reflect.TypeOf((*T)(nil)).Elem())
+	return keys
+}
+
 type IntectorFn func(Container, context.Context) (out any, err error)
 
 var (
-	injectors = map[reflect.Type]IntectorFn{}
+	injectorsMu sync.RWMutex
+	injectors   = map[reflect.Type]IntectorFn{}
 )
 
 func InjectorOf(structType reflect.Type) IntectorFn {
@@ -50,62 +65,26 @@ func InjectorOf(structType reflect.Type) IntectorFn {
 		panic(ErrNotStruct)
 	}
 
-	if injector, exists := injectors[structType]; exists {
+	injectorsMu.RLock()
+	injector, exists := injectors[structType]
+	injectorsMu.RUnlock()
+	if exists {
 		return injector
 	}
 
-	var depsFieldIdx []int
-	var depsFieldKey []reflect.Type
-
-	for fieldIndex := 0; fieldIndex < structTypeNoPtr.NumField(); fieldIndex++ {
-		field := structTypeNoPtr.Field(fieldIndex)
-		if !field.IsExported() {
-			continue
-		}
-
-		if _, hasTag := field.Tag.Lookup("inject"); !hasTag {
-			continue
-		}
-
-		depsFieldIdx = append(depsFieldIdx, fieldIndex)
-		depsFieldKey = append(depsFieldKey, KeyOf(field.Type))
-	}
+	depsFieldIdx, depsFieldKey := injectFields(structTypeNoPtr)
 
-	injector := func(ctn Container, ctx context.Context) (out any, err error) {
+	injector = func(ctn Container, ctx context.Context) (out any, err error) {
 		nptr_ptr := reflect.New(structTypeNoPtr) // Pointer Struct
 		nptr_val := nptr_ptr.Elem()              // Value  Struct
 
 		for i, fieldIndex := range depsFieldIdx {
-			// resolve dependency
-			depk := depsFieldKey[i]
-			if dep, e := ctn.Get(depk, ctx); e != nil {
-				// automatically inject Struct (prototype scoped)
-				if errors.Is(e, ErrCandidateNotFound) {
-					st := depk
-					if st.Kind() == reflect.Pointer {
-						st = st.Elem()
-					}
-					if st.Kind() == reflect.Struct {
-						injector := InjectorOf(depk)
-						if dep, ierr := injector(ctn, ctx); ierr != nil {
-							e = ierr
-						} else {
-							// instance created - initializers/post construct
-							if i, ok := dep.(Initializable); ok {
-								i.Initialize()
-							}
-
-							nptr_val.Field(fieldIndex).Set(reflect.ValueOf(dep))
-							continue
-						}
-					}
-				}
-
-				err = errors.Join(fmt.Errorf(`cannot resolve dependency "%s" for "%s"`, depk.String(), structType.String()), e)
+			dep, e := resolveField(ctn, ctx, depsFieldKey[i], structType)
+			if e != nil {
+				err = e
 				return
-			} else {
-				nptr_val.Field(fieldIndex).Set(reflect.ValueOf(dep))
 			}
+			nptr_val.Field(fieldIndex).Set(reflect.ValueOf(dep))
 		}
 
 		if isPointer {
@@ -118,6 +97,71 @@ func InjectorOf(structType reflect.Type) IntectorFn {
 
 		return
 	}
+	injectorsMu.Lock()
+	defer injectorsMu.Unlock()
+	if existing, exists := injectors[structType]; exists {
+		return existing
+	}
 	injectors[structType] = injector
 	return injector
 }
+
+// injectFields the index and the key of the exported fields with the `inject` tag
+func injectFields(structType reflect.Type) (indexes []int, keys []reflect.Type) {
+	if structType.Kind() == reflect.Pointer {
+		structType = structType.Elem()
+	}
+	for fieldIndex := 0; fieldIndex < structType.NumField(); fieldIndex++ {
+		field := structType.Field(fieldIndex)
+		if !field.IsExported() {
+			continue
+		}
+
+		if _, hasTag := field.Tag.Lookup("inject"); !hasTag {
+			continue
+		}
+
+		indexes = append(indexes, fieldIndex)
+		keys = append(keys, KeyOf(field.Type))
+	}
+	return
+}
+
+// isInjectable checks if the key is a struct (or *struct), injected automatically
+// when not registered (see resolveField)
+func isInjectable(key reflect.Type) bool {
+	if key.Kind() == reflect.Pointer {
+		key = key.Elem()
+	}
+	return key.Kind() == reflect.Struct
+}
+
+// resolveField resolves the dependency of an `inject` field of the struct
+func resolveField(ctn Container, ctx context.Context, depk reflect.Type, structType reflect.Type) (any, error) {
+	dep, e := ctn.Get(depk, ctx)
+	if e == nil {
+		return dep, nil
+	}
+
+	// automatically inject Struct (prototype scoped)
+	if errors.Is(e, ErrCandidateNotFound) {
+		if isInjectable(depk) {
+			injector := InjectorOf(depk)
+			if dep, ierr := injector(ctn, ctx); ierr != nil {
+				e = ierr
+			} else {
+				// instance created - initializers/post construct
+				if i, ok := dep.(Initializable); ok {
+					i.Initialize()
+				}
+				return dep, nil
+			}
+		}
+	}
+
+	if errors.As(e, new(*ResolutionError)) {
+		// already describes the full resolution path
+		return nil, e
+	}
+	return nil, errors.Join(fmt.Errorf(`cannot resolve dependency "%s" for "%s"`, depk.String(), structType.String()), e)
+}
@@ -0,0 +1,222 @@
+package di
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics receives the activity of the container. Implementations must be safe
+// for concurrent use.
+//
+// Example:
+//
+//	ctn := di.New(nil, di.WithMetrics(di.ExpvarMetrics("di")))
+//
+// Live instances of a custom scope (Ex. "request") are the created instances minus
+// the disposed ones, a growing count usually means the scope is never destroyed.
+type Metrics interface {
+	// ComponentCreated an instance of the factory was created (constructor and initializers)
+	ComponentCreated(factory *Factory, duration time.Duration)
+
+	// ComponentDisposed an instance of the factory was disposed
+	ComponentDisposed(factory *Factory, duration time.Duration)
+
+	// ResolutionFailed the requested key could not be resolved (Container.Get)
+	ResolutionFailed(key reflect.Type, kind ResolutionErrorKind)
+}
+
+// WithMetrics sets the Metrics of the container
+func WithMetrics(metrics Metrics) ContainerConfig {
+	return func(c *container) {
+		c.metrics = metrics
+	}
+}
+
+// resolutionErrorKind classifies the error for Metrics
+func resolutionErrorKind(err error) ResolutionErrorKind {
+	var resErr *ResolutionError
+	if errors.As(err, &resErr) {
+		return resErr.Kind
+	}
+	if errors.As(err, new(*CycleError)) {
+		return ResolutionCycle
+	}
+	return ResolutionOther
+}
+
+// DefaultMetricsBuckets upper bounds (in seconds) of the creation time histograms
+var DefaultMetricsBuckets = []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1, 5}
+
+// ExpvarMetrics creates a Metrics that publishes the container activity as an expvar.Map
+// with the given name. The Metrics created with the same name (Ex. the containers of the
+// tests) share the counters. Panics if the name is published with another type of
+// expvar.Var.
+//
+//	{
+//	  "created": {"*app.UserService": 1, ...},        // instances created per key (all factories of the key)
+//	  "created_scope": {"singleton": 10, ...},        // instances created per scope
+//	  "creation_seconds": {"singleton": {...}, ...},  // creation time histogram per scope
+//	  "disposed_scope": {"request": 20, ...},         // instances disposed per scope
+//	  "live_scope": {"request": 2, ...},              // live instances per custom scope
+//	  "resolution_errors": {"no_candidate": 1, ...}   // resolution errors per kind
+//	}
+func ExpvarMetrics(name string) Metrics {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	root, _ := expvar.Get(name).(*expvar.Map)
+	if root == nil {
+		root = expvar.NewMap(name)
+	}
+
+	return &expvarMetrics{
+		created:          expvarSubMap(root, "created"),
+		createdScope:     expvarSubMap(root, "created_scope"),
+		creationSeconds:  expvarSubMap(root, "creation_seconds"),
+		disposedScope:    expvarSubMap(root, "disposed_scope"),
+		liveScope:        expvarSubMap(root, "live_scope"),
+		resolutionErrors: expvarSubMap(root, "resolution_errors"),
+	}
+}
+
+// expvarMu the maps are shared by the Metrics of the same name (see ExpvarMetrics)
+var expvarMu sync.Mutex
+
+// expvarSubMap the map published with the key, created if not exists
+func expvarSubMap(root *expvar.Map, key string) *expvar.Map {
+	if m, ok := root.Get(key).(*expvar.Map); ok {
+		return m
+	}
+	m := new(expvar.Map).Init()
+	root.Set(key, m)
+	return m
+}
+
+type expvarMetrics struct {
+	created          *expvar.Map
+	createdScope     *expvar.Map
+	creationSeconds  *expvar.Map
+	disposedScope    *expvar.Map
+	liveScope        *expvar.Map
+	resolutionErrors *expvar.Map
+}
+
+func (m *expvarMetrics) ComponentCreated(factory *Factory, duration time.Duration) {
+	m.created.Add(factory.String(), 1)
+	m.createdScope.Add(factory.scope, 1)
+	if isCustomScope(factory.scope) {
+		m.liveScope.Add(factory.scope, 1)
+	}
+	m.histogram(factory.scope).Observe(duration.Seconds())
+}
+
+func (m *expvarMetrics) ComponentDisposed(factory *Factory, duration time.Duration) {
+	m.disposedScope.Add(factory.scope, 1)
+	if isCustomScope(factory.scope) {
+		m.liveScope.Add(factory.scope, -1)
+	}
+}
+
+func (m *expvarMetrics) ResolutionFailed(key reflect.Type, kind ResolutionErrorKind) {
+	m.resolutionErrors.Add(string(kind), 1)
+}
+
+func (m *expvarMetrics) histogram(scope string) *Histogram {
+	if h, ok := m.creationSeconds.Get(scope).(*Histogram); ok {
+		return h
+	}
+
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+
+	if h, ok := m.creationSeconds.Get(scope).(*Histogram); ok {
+		return h
+	}
+	h := NewHistogram(DefaultMetricsBuckets)
+	m.creationSeconds.Set(scope, h)
+	return h
+}
+
+func isCustomScope(scope string) bool {
+	return scope != SCOPE_SINGLETON && scope != SCOPE_PROTOTYPE
+}
+
+// Histogram a cumulative histogram that can be published with expvar.
+type Histogram struct {
+	bounds []float64
+	counts []atomic.Uint64 // len(bounds) + 1 (+Inf)
+	count  atomic.Uint64
+	sum    atomic.Uint64 // float64 bits
+}
+
+// NewHistogram creates a Histogram with the given upper bounds (sorted)
+func NewHistogram(bounds []float64) *Histogram {
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+// Observe adds a value to the histogram
+func (h *Histogram) Observe(v float64) {
+	i := 0
+	for i < len(h.bounds) && v > h.bounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.count.Add(1)
+	for {
+		old := h.sum.Load()
+		sum := math.Float64frombits(old) + v
+		if h.sum.CompareAndSwap(old, math.Float64bits(sum)) {
+			break
+		}
+	}
+}
+
+// Count the number of observations
+func (h *Histogram) Count() uint64 {
+	return h.count.Load()
+}
+
+// Sum the sum of the observations
+func (h *Histogram) Sum() float64 {
+	return math.Float64frombits(h.sum.Load())
+}
+
+// Buckets the cumulative count of each upper bound, the last one is +Inf
+func (h *Histogram) Buckets() []uint64 {
+	buckets := make([]uint64, len(h.counts))
+	var total uint64
+	for i := range h.counts {
+		total += h.counts[i].Load()
+		buckets[i] = total
+	}
+	return buckets
+}
+
+// String implements expvar.Var
+func (h *Histogram) String() string {
+	var b strings.Builder
+	b.WriteString(`{"buckets": {`)
+	for i, count := range h.Buckets() {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		le := "+Inf"
+		if i < len(h.bounds) {
+			le = strconv.FormatFloat(h.bounds[i], 'g', -1, 64)
+		}
+		fmt.Fprintf(&b, "%q: %d", le, count)
+	}
+	fmt.Fprintf(&b, `}, "count": %d, "sum": %s}`, h.Count(), strconv.FormatFloat(h.Sum(), 'g', -1, 64))
+	return b.String()
+}
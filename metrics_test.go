@@ -0,0 +1,100 @@
+package di
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testDisposableScope keeps the disposers until Destroy
+type testDisposableScope struct {
+	disposers []DisposableAdapter
+}
+
+func (s *testDisposableScope) Get(ctx context.Context, factory *Factory, createObject CreateObjectFunc) (any, error) {
+	obj, disposer, err := createObject()
+	if disposer != nil {
+		s.disposers = append(s.disposers, disposer)
+	}
+	return obj, err
+}
+
+func (s *testDisposableScope) Remove(*Factory, any) (any, error) { return nil, nil }
+
+func (s *testDisposableScope) Destroy() {
+	for _, disposer := range s.disposers {
+		disposer.Dispose()
+	}
+	s.disposers = nil
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	// the counters are shared by the Metrics of the same name (go test -count=N)
+	metrics := ExpvarMetrics("di_test_metrics")
+	vars := expvar.Get("di_test_metrics").(*expvar.Map)
+	get := func(name, key string) int64 {
+		if v, ok := vars.Get(name).(*expvar.Map).Get(key).(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	count := func() uint64 {
+		if h, ok := vars.Get("creation_seconds").(*expvar.Map).Get("request").(*Histogram); ok {
+			return h.Count()
+		}
+		return 0
+	}
+
+	created, createdScope, live, disposed, errs, histogram := get("created", "*di.testResolveLeaf"),
+		get("created_scope", "prototype"), get("live_scope", "request"), get("disposed_scope", "request"),
+		get("resolution_errors", "no_candidate"), count()
+
+	scope := &testDisposableScope{}
+	ctn := New(nil, WithMetrics(metrics))
+	require.NoError(t, ctn.RegisterScope("request", scope))
+
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} }, Scoped("request"))
+	ctn.Register(func(l *testResolveLeaf) *testResolveMiddle { return &testResolveMiddle{} }, Prototype)
+	require.NoError(t, ctn.Initialize())
+
+	for i := 0; i < 3; i++ {
+		_, err := GetFrom[*testResolveMiddle](ctn)
+		require.NoError(t, err)
+	}
+	_, err := GetFrom[*testResolveRoot](ctn)
+	require.Error(t, err)
+
+	require.Equal(t, created+3, get("created", "*di.testResolveLeaf"))
+	require.Equal(t, createdScope+3, get("created_scope", "prototype"))
+	require.Equal(t, live+3, get("live_scope", "request"))
+	require.Equal(t, errs+1, get("resolution_errors", "no_candidate"))
+	require.Equal(t, histogram+3, count())
+
+	scope.Destroy()
+	require.Equal(t, live, get("live_scope", "request"))
+	require.Equal(t, disposed+3, get("disposed_scope", "request"))
+
+	// valid JSON
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(vars.String()), &decoded))
+
+	// same counters
+	ExpvarMetrics("di_test_metrics").ResolutionFailed(Key[*testResolveRoot](), ResolutionNoCandidate)
+	require.Equal(t, errs+2, get("resolution_errors", "no_candidate"))
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram([]float64{1, 2})
+	h.Observe(0.5)
+	h.Observe(1)
+	h.Observe(1.5)
+	h.Observe(10)
+
+	require.Equal(t, uint64(4), h.Count())
+	require.Equal(t, 13.0, h.Sum())
+	require.Equal(t, []uint64{2, 3, 4}, h.Buckets())
+	require.Equal(t, `{"buckets": {"1": 2, "2": 3, "+Inf": 4}, "count": 4, "sum": 13}`, h.String())
+}
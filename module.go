@@ -0,0 +1,72 @@
+package di
+
+import (
+	"context"
+	"reflect"
+)
+
+// Module identifies the module of a component. Combined with Private, it limits where
+// the component can be injected. Usually used in a Stereotype.
+//
+// Example:
+//
+//	var Payments = di.Stereotype(di.Module("payments"), di.Private)
+//
+//	di.Register(NewStripeClient, Payments)               // injectable only in "payments"
+//	di.Register(NewPaymentService, Payments, di.Exported) // injectable everywhere
+func Module(name string) FactoryConfig {
+	return func(f *Factory) {
+		f.module = name
+	}
+}
+
+// Private indicates that the component can only be injected in components of the same
+// Module. Resolution fails with ErrNotVisible anywhere else, including the components
+// of other modules and the Get calls outside of a component constructor.
+func Private(f *Factory) {
+	f.private = true
+}
+
+// Exported indicates that the component can be injected everywhere (default). Reverts
+// the Private of a stereotype.
+func Exported(f *Factory) {
+	f.private = false
+}
+
+// checkVisibility checks that the factory can be injected in the component being
+// created (see Private)
+func checkVisibility(key reflect.Type, factory *Factory, ctx context.Context) error {
+	if !factory.private {
+		return nil
+	}
+	if path := getCreationPath(ctx); path != nil && path.factory.module == factory.module {
+		return nil
+	}
+	return &ResolutionError{
+		Kind: ResolutionNotVisible,
+		Key:  key,
+		Path: resolutionPath(ctx, ResolutionStep{Key: key, Factory: factory}),
+		Err:  ErrNotVisible,
+	}
+}
+
+// checkProviderVisibility checks that the component returned by a provider can be
+// injected in the component being created
+func (c *container) checkProviderVisibility(key reflect.Type, ctx context.Context) error {
+	if !c.Contains(key) {
+		// provided by the parent
+		if parent, ok := c.parent.(*container); ok {
+			return parent.checkProviderVisibility(key, ctx)
+		}
+		return nil
+	}
+	factory := c.getKeyPlan(key)
+	if factory == nil {
+		var err error
+		if factory, err = c.resolveFactory(c.GetParam(key), ctx); err != nil {
+			// returned by the provider
+			return nil
+		}
+	}
+	return checkVisibility(key, factory, ctx)
+}
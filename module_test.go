@@ -0,0 +1,101 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testPaymentGateway struct{}
+
+type testPaymentService struct{ gateway *testPaymentGateway }
+
+type testOrderCheckout struct{ service *testPaymentService }
+
+var testPayments = Stereotype(Module("payments"), Private)
+
+func TestModule(t *testing.T) {
+	newContainer := func() Container {
+		ctn := New(nil)
+		ctn.Register(func() *testPaymentGateway { return &testPaymentGateway{} }, testPayments)
+		ctn.Register(func(g *testPaymentGateway) *testPaymentService {
+			return &testPaymentService{gateway: g}
+		}, testPayments, Exported)
+		return ctn
+	}
+
+	t.Run("visible in the module", func(t *testing.T) {
+		ctn := newContainer()
+		ctn.Register(func(s *testPaymentService) *testOrderCheckout { return &testOrderCheckout{service: s} })
+		require.NoError(t, ctn.Validate())
+		require.NoError(t, ctn.Initialize())
+
+		checkout, err := GetFrom[*testOrderCheckout](ctn)
+		require.NoError(t, err)
+		require.NotNil(t, checkout.service.gateway)
+
+		require.Len(t, ctn.Filter(Module("payments")).factories, 2)
+	})
+
+	t.Run("not visible outside the module", func(t *testing.T) {
+		ctn := newContainer()
+		ctn.Register(func(g *testPaymentGateway) *testOrderCheckout { return &testOrderCheckout{} }, Module("orders"))
+		err := ctn.Validate()
+		require.ErrorIs(t, err, ErrNotVisible)
+		require.NoError(t, ctn.Initialize())
+
+		_, err = GetFrom[*testOrderCheckout](ctn)
+		require.ErrorIs(t, err, ErrNotVisible)
+		var resErr *ResolutionError
+		require.True(t, errors.As(err, &resErr))
+		require.Equal(t, ResolutionNotVisible, resErr.Kind)
+		require.Contains(t, err.Error(), `*di.testPaymentGateway is private to module "payments", not visible to *di.testOrderCheckout (module "orders")`)
+
+		_, err = GetFrom[*testPaymentGateway](ctn)
+		require.ErrorIs(t, err, ErrNotVisible)
+		require.Contains(t, err.Error(), `*di.testPaymentGateway is private to module "payments"`)
+	})
+
+	t.Run("provider", func(t *testing.T) {
+		ctn := newContainer()
+		ctn.Register(func(g Provider[*testPaymentGateway]) *testOrderCheckout { return &testOrderCheckout{} })
+		require.NoError(t, ctn.Initialize())
+
+		_, err := GetFrom[*testOrderCheckout](ctn)
+		require.ErrorIs(t, err, ErrNotVisible)
+	})
+
+	t.Run("provider in a child", func(t *testing.T) {
+		ctn := newContainer()
+		require.NoError(t, ctn.Initialize())
+
+		child := ctn.NewChild()
+		child.Register(func(g Provider[*testPaymentGateway]) *testOrderCheckout { return &testOrderCheckout{} })
+		child.Register(func(g Unmanaged[*testPaymentGateway]) *testResolveLeaf { return &testResolveLeaf{} })
+		require.NoError(t, child.Initialize())
+
+		_, err := GetFrom[*testOrderCheckout](child)
+		require.ErrorIs(t, err, ErrNotVisible)
+		_, err = GetFrom[*testResolveLeaf](child)
+		require.ErrorIs(t, err, ErrNotVisible)
+	})
+
+	t.Run("all of", func(t *testing.T) {
+		var inModule []*testPaymentGateway
+		ctn := newContainer()
+		ctn.Register(func(ctx context.Context, c Container) *testResolveLeaf {
+			inModule, _ = AllOf[*testPaymentGateway](c, ctx)
+			return &testResolveLeaf{}
+		}, testPayments, Exported)
+		require.NoError(t, ctn.Initialize())
+
+		gateways, err := AllOf[*testPaymentGateway](ctn, context.Background())
+		require.NoError(t, err)
+		require.Empty(t, gateways)
+
+		MustGetFrom[*testResolveLeaf](ctn)
+		require.Len(t, inModule, 1)
+	})
+}
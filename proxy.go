@@ -0,0 +1,73 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ContextualProxy resolves instances of T from the scope of the given context.
+// It is the base of the proxies created by ScopedProxy.
+//
+// See ScopedProxy
+type ContextualProxy[T any] struct {
+	TypeBase[T]
+	supplier func(ctx context.Context) (any, error)
+}
+
+// Get the instance of T bound to the scope of the context
+func (p ContextualProxy[T]) Get(ctx context.Context) (o T, e error) {
+	if v, err := p.supplier(ctx); err != nil {
+		e = err
+	} else {
+		o = v.(T)
+	}
+	return
+}
+
+// ScopedProxy allows singletons to depend on a scoped (Ex. request) component of
+// an interface type T. Instead of capturing a single instance of the component
+// forever, the singleton receives a proxy whose methods resolve the real instance
+// from the scope of the context of each call.
+//
+// Example:
+//
+//	type UserRepository interface {
+//		Find(ctx context.Context, id string) (*User, error)
+//	}
+//
+//	type userRepositoryProxy struct {
+//		di.ContextualProxy[UserRepository]
+//	}
+//
+//	func (p userRepositoryProxy) Find(ctx context.Context, id string) (*User, error) {
+//		repository, err := p.Get(ctx)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return repository.Find(ctx, id)
+//	}
+//
+//	di.Register(NewUserRepository, di.Scoped("request"), di.ScopedProxy(func(p di.ContextualProxy[UserRepository]) UserRepository {
+//		return userRepositoryProxy{p}
+//	}))
+//
+// The proxy is injected only into singletons; components with shorter lifecycles
+// receive the real instance.
+func ScopedProxy[T any](newProxy func(ContextualProxy[T]) T) FactoryConfig {
+	key := Key[T]()
+	if key.Kind() != reflect.Interface {
+		panic(fmt.Errorf("ScopedProxy: %v is not an interface", key))
+	}
+	return func(f *Factory) {
+		f.proxyKey = key
+		f.proxy = func(supplier func(ctx context.Context) (any, error)) any {
+			return newProxy(ContextualProxy[T]{supplier: supplier})
+		}
+	}
+}
+
+// proxyFor checks if a proxy of the factory can be injected in the key
+func (f *Factory) proxyFor(key reflect.Type) bool {
+	return f.proxy != nil && f.proxyKey.AssignableTo(key)
+}
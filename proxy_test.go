@@ -0,0 +1,89 @@
+package di
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRequestScopeKey struct{}
+
+// testRequestScope keeps one instance per factory in the context
+type testRequestScope struct{}
+
+func (s *testRequestScope) Get(ctx context.Context, factory *Factory, createObject CreateObjectFunc) (any, error) {
+	objects, ok := ctx.Value(testRequestScopeKey{}).(*sync.Map)
+	if !ok {
+		return nil, ErrContextRequired
+	}
+	if obj, exists := objects.Load(factory.Id()); exists {
+		return obj, nil
+	}
+	obj, _, err := createObject()
+	if err != nil {
+		return nil, err
+	}
+	objects.Store(factory.Id(), obj)
+	return obj, nil
+}
+
+func (s *testRequestScope) Remove(*Factory, any) (any, error) { return nil, nil }
+
+func (s *testRequestScope) Destroy() {}
+
+func newTestRequest() context.Context {
+	return context.WithValue(context.Background(), testRequestScopeKey{}, &sync.Map{})
+}
+
+type testServiceAProxy struct {
+	ContextualProxy[testServiceA]
+}
+
+func (p testServiceAProxy) target(ctx context.Context) testServiceA {
+	s, err := p.Get(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (p testServiceAProxy) Name() string       { return "proxy" }
+func (p testServiceAProxy) Event(event string) {}
+func (p testServiceAProxy) IsA()               {}
+
+func TestScopedProxy(t *testing.T) {
+	ctn := New(nil)
+	require.NoError(t, ctn.RegisterScope("request", &testRequestScope{}))
+
+	count := 0
+	ctn.Register(func() testServiceA {
+		count++
+		return newTestServiceA("req-"+strconv.Itoa(count), nil)
+	}, Scoped("request"), ScopedProxy(func(p ContextualProxy[testServiceA]) testServiceA {
+		return testServiceAProxy{p}
+	}))
+
+	var proxy testServiceAProxy
+	ctn.Register(func(s testServiceA) {
+		proxy = s.(testServiceAProxy)
+	}, Startup(100))
+
+	// not a scope violation
+	VerifyFrom(t, ctn)
+
+	require.NoError(t, ctn.Initialize())
+	require.Equal(t, 0, count, "proxy must not resolve the target on injection")
+
+	r1, r2 := newTestRequest(), newTestRequest()
+	require.Equal(t, "req-1", proxy.target(r1).Name())
+	require.Equal(t, "req-1", proxy.target(r1).Name())
+	require.Equal(t, "req-2", proxy.target(r2).Name())
+
+	// outside the singleton, the real instance is resolved
+	s, err := GetFrom[testServiceA](ctn, r1)
+	require.NoError(t, err)
+	require.Equal(t, "req-1", s.Name())
+}
@@ -0,0 +1,377 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scheduled runs the component, a func([context.Context], [dep A..N]) [error], on the given
+// schedule after Initialize, until the container is destroyed. Each run resolves the
+// dependencies in a new "job" scope (see SCOPE_JOB), disposed at the end of the run. Runs
+// never overlap: the runs missed while the previous one is running are skipped. Errors
+// are logged, the status is available in Container.Workers.
+//
+// The spec is "@every <duration>" (Ex. "@every 30s"), one of "@yearly", "@monthly",
+// "@weekly", "@daily", "@hourly" or a cron expression with 5 fields (minute, hour, day of
+// month, month and day of week, in the time zone of the Clock), supporting "*", lists
+// (1,5), ranges (1-5) and steps (*/15). Registration fails with ErrInvalidSchedule for
+// an invalid spec.
+//
+// Example:
+//
+//	di.Register(func(ctx context.Context, repo *SessionRepository) error {
+//		return repo.DeleteExpired(ctx)
+//	}, di.Scheduled("*/15 * * * *"), di.Jitter(time.Minute))
+func Scheduled(spec string) FactoryConfig {
+	s, err := parseSchedule(spec)
+	if err != nil {
+		err = fmt.Errorf("%w %q: %v", ErrInvalidSchedule, spec, err)
+	}
+	return func(f *Factory) {
+		f.schedule = &taskSchedule{spec: spec, schedule: s, err: err}
+		f.scope = SCOPE_JOB
+	}
+}
+
+// Jitter delays each run of a Scheduled component by a random duration up to max, to
+// spread the load of many instances of the application.
+func Jitter(max time.Duration) FactoryConfig {
+	return func(f *Factory) {
+		f.jitter = max
+	}
+}
+
+// Clock the time source of the Scheduled components (see WithClock)
+type Clock interface {
+	Now() time.Time
+
+	// Sleep waits for the duration, returns false if the context is canceled before
+	Sleep(ctx context.Context, d time.Duration) bool
+}
+
+// WithClock sets the time source of the Scheduled components and of the workers (restart
+// delays and WorkerStatus.Started). Defaults to the system clock. Tests can use a fake
+// clock (see ditest.NewClock).
+func WithClock(clock Clock) ContainerConfig {
+	return func(c *container) {
+		c.clock = clock
+	}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// taskSchedule see Scheduled
+type taskSchedule struct {
+	spec     string
+	schedule schedule
+	err      error // invalid spec, returned by ShouldRegister
+}
+
+// checkScheduled checks the spec and the shape of a Scheduled component
+func checkScheduled(f *Factory) error {
+	if f.schedule.err != nil {
+		return f.schedule.err
+	}
+	if f.isReference || f.ReturnsValue() {
+		return fmt.Errorf("%w: %v must be a func([context.Context], [dep A..N]) [error]", ErrInvalidSchedule, f.factoryType)
+	}
+	return nil
+}
+
+// scheduledTask runs a Scheduled component, supervised as a Worker (see startWorkers)
+type scheduledTask struct {
+	c       *container
+	factory *Factory
+	worker  *worker
+}
+
+func (t *scheduledTask) Run(ctx context.Context) error {
+	clock := t.c.clock
+	schedule := t.factory.schedule.schedule
+
+	next := clock.Now()
+	for {
+		now := clock.Now()
+		if !next.After(now) {
+			// skips the runs missed
+			if every, ok := schedule.(everySchedule); ok {
+				next = next.Add((now.Sub(next)/every.interval + 1) * every.interval)
+			} else if next = schedule.next(now); next.IsZero() {
+				return nil
+			}
+		}
+
+		delay := next.Sub(now)
+		if t.factory.jitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(t.factory.jitter)))
+		}
+		if !clock.Sleep(ctx, delay) {
+			return nil
+		}
+
+		err := t.run(ctx)
+		if err != nil {
+			t.c.log(LogError, "[di] scheduled task failed", slog.String("task", t.factory.String()), slog.Any("error", err))
+		}
+		t.worker.mu.Lock()
+		t.worker.status.Err = err
+		t.worker.mu.Unlock()
+	}
+}
+
+// run runs the task once, in a new job scope
+func (t *scheduledTask) run(ctx context.Context) (err error) {
+	job := &jobInstances{objects: make(map[int]any)}
+	defer job.dispose()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("scheduled task panic: %v", r)
+		}
+	}()
+
+	_, _, err = t.c.GetObjectFactory(t.factory, false, context.WithValue(ctx, ctxJobKey, job))()
+	return
+}
+
+type ctxJobKeyType int // unexported type for ctxJobKey to avoid collisions.
+
+var ctxJobKey ctxJobKeyType
+
+// jobInstances the instances of a run of a Scheduled component (see SCOPE_JOB)
+type jobInstances struct {
+	mu        sync.Mutex
+	objects   map[int]any
+	disposers []DisposableAdapter
+}
+
+// dispose the instances of the job, in reverse order of creation
+func (j *jobInstances) dispose() {
+	j.mu.Lock()
+	disposers := j.disposers
+	j.disposers = nil
+	j.mu.Unlock()
+
+	for i := len(disposers) - 1; i >= 0; i-- {
+		disposers[i].Dispose()
+	}
+}
+
+// scopeJob one instance per run of a Scheduled component
+type scopeJob struct{}
+
+func (s *scopeJob) Get(ctx context.Context, factory *Factory, createObject CreateObjectFunc) (any, error) {
+	job, ok := ctx.Value(ctxJobKey).(*jobInstances)
+	if !ok {
+		return nil, ErrContextRequired
+	}
+
+	job.mu.Lock()
+	obj, exists := job.objects[factory.Id()]
+	job.mu.Unlock()
+	if exists {
+		return obj, nil
+	}
+
+	obj, disposer, err := createObject()
+	if err != nil {
+		return nil, err
+	}
+
+	job.mu.Lock()
+	job.objects[factory.Id()] = obj
+	if disposer != nil {
+		job.disposers = append(job.disposers, disposer)
+	}
+	job.mu.Unlock()
+	return obj, nil
+}
+
+func (s *scopeJob) Remove(*Factory, any) (any, error) {
+	return nil, nil
+}
+
+func (s *scopeJob) Destroy() {}
+
+// schedule the times of the runs of a Scheduled component
+type schedule interface {
+	// next the first time after t, zero if none
+	next(t time.Time) time.Time
+}
+
+// everySchedule "@every <duration>"
+type everySchedule struct {
+	interval time.Duration
+}
+
+func (s everySchedule) next(t time.Time) time.Time {
+	return t.Add(s.interval)
+}
+
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronSchedule a cron expression, each field is a bit set of the allowed values
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool // day of month/week not restricted
+}
+
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if every, ok := strings.CutPrefix(spec, "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(every))
+		if err != nil {
+			return nil, err
+		}
+		if interval <= 0 {
+			return nil, errors.New("interval must be positive")
+		}
+		return everySchedule{interval: interval}, nil
+	}
+
+	if alias, ok := cronAliases[spec]; ok {
+		spec = alias
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, found %d", len(fields))
+	}
+
+	s := &cronSchedule{
+		domStar: strings.HasPrefix(fields[2], "*"),
+		dowStar: strings.HasPrefix(fields[4], "*"),
+	}
+	var err error
+	if s.minute, err = parseCronField(fields[0], 0, 59); err != nil {
+		return nil, fmt.Errorf("minute: %w", err)
+	}
+	if s.hour, err = parseCronField(fields[1], 0, 23); err != nil {
+		return nil, fmt.Errorf("hour: %w", err)
+	}
+	if s.dom, err = parseCronField(fields[2], 1, 31); err != nil {
+		return nil, fmt.Errorf("day of month: %w", err)
+	}
+	if s.month, err = parseCronField(fields[3], 1, 12); err != nil {
+		return nil, fmt.Errorf("month: %w", err)
+	}
+	if s.dow, err = parseCronField(fields[4], 0, 7); err != nil {
+		return nil, fmt.Errorf("day of week: %w", err)
+	}
+	if s.dow&(1<<7) != 0 {
+		// 7 = sunday
+		s.dow |= 1
+	}
+
+	if s.next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).IsZero() {
+		return nil, errors.New("never runs")
+	}
+	return s, nil
+}
+
+// parseCronField "*", "5", "1-5", "*/15", "1-30/5" or a list of them ("1,15,30")
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepStr); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+		}
+
+		from, to := min, max
+		if rng != "*" {
+			fromStr, toStr, isRange := strings.Cut(rng, "-")
+			var err error
+			if from, err = strconv.Atoi(fromStr); err != nil {
+				return 0, fmt.Errorf("invalid value %q", fromStr)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(toStr); err != nil {
+					return 0, fmt.Errorf("invalid value %q", toStr)
+				}
+			} else if hasStep {
+				to = max
+			}
+		}
+		if from < min || to > max || from > to {
+			return 0, fmt.Errorf("%q out of range [%d, %d]", part, min, max)
+		}
+
+		for v := from; v <= to; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (s *cronSchedule) next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+
+	// leap years included
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches the day of month OR the day of week match, unless one of them is "*"
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
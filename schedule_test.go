@@ -0,0 +1,62 @@
+package di
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSchedule(t *testing.T) {
+	start := time.Date(2024, 1, 31, 10, 7, 30, 0, time.UTC) // wednesday
+
+	tests := []struct {
+		spec string
+		next time.Time
+	}{
+		{"@every 30s", time.Date(2024, 1, 31, 10, 8, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, 1, 31, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, 1, 31, 10, 15, 0, 0, time.UTC)},
+		{"0,30 9-17 * * 1-5", time.Date(2024, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{"0 12 * * 0", time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, 2, 4, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 5", time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)}, // first day of month or friday
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			s, err := parseSchedule(tt.spec)
+			require.NoError(t, err)
+			require.Equal(t, tt.next, s.next(start))
+		})
+	}
+
+	for _, spec := range []string{"", "@every", "@every -1s", "* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *", "0 0 30 2 *"} {
+		_, err := parseSchedule(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestScheduledRegistration(t *testing.T) {
+	ctn := New(nil)
+	err := ctn.ShouldRegister(func(ctx context.Context) error { return nil }, Scheduled("@weekly"))
+	require.NoError(t, err)
+	require.Equal(t, SCOPE_JOB, ctn.Filter(Scoped(SCOPE_JOB)).factories[0].Scope())
+
+	err = ctn.ShouldRegister(func() error { return nil }, Scheduled("* *"))
+	require.ErrorIs(t, err, ErrInvalidSchedule)
+	require.ErrorContains(t, err, `invalid schedule "* *": expected 5 fields, found 2`)
+
+	err = ctn.ShouldRegister(func() *testResolveLeaf { return nil }, Scheduled("@daily"))
+	require.ErrorIs(t, err, ErrInvalidSchedule)
+
+	// job scoped components need a run
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} }, Scoped(SCOPE_JOB))
+	require.NoError(t, ctn.Initialize())
+	_, err = GetFrom[*testResolveLeaf](ctn)
+	require.ErrorIs(t, err, ErrContextRequired)
+	require.NoError(t, ctn.Destroy())
+}
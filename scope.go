@@ -7,6 +7,7 @@ import (
 const (
 	SCOPE_SINGLETON string = "singleton"
 	SCOPE_PROTOTYPE string = "prototype"
+	SCOPE_JOB       string = "job" // a run of a Scheduled component
 )
 
 type CreateObjectFunc func() (any, DisposableAdapter, error)
@@ -9,47 +9,72 @@ func newSingletonScope() *scopeSingleton {
 	return &scopeSingleton{
 		objects:   make(map[int]any),
 		disposers: make(map[int]DisposableAdapter),
+		calls:     make(map[int]*singletonCall),
 	}
 }
 
-// todo sync.Map?
 type scopeSingleton struct {
 	m         sync.RWMutex
 	objects   map[int]any               // Cache of singleton objects
 	disposers map[int]DisposableAdapter // Cache of disposers
+	calls     map[int]*singletonCall    // Singletons in creation
+}
+
+// singletonCall a singleton in creation, concurrent requests wait for the result
+// instead of invoking the constructor again.
+type singletonCall struct {
+	done chan struct{}
+	obj  any
+	err  error
 }
 
 func (s *scopeSingleton) Get(ctx context.Context, factory *Factory, createObject CreateObjectFunc) (any, error) {
 	fid := factory.Id()
-	if singleton, exist := s.objects[fid]; exist {
+	s.m.RLock()
+	singleton, exist := s.objects[fid]
+	s.m.RUnlock()
+	if exist {
 		return singleton, nil
 	}
 
-	if singleton, disposer, err := createObject(); err != nil {
-		return nil, err
-	} else {
+	s.m.Lock()
+	if singleton, exist := s.objects[fid]; exist {
+		s.m.Unlock()
+		return singleton, nil
+	}
+	if call, inCreation := s.calls[fid]; inCreation {
+		s.m.Unlock()
+		<-call.done
+		return call.obj, call.err
+	}
+	call := &singletonCall{done: make(chan struct{}), err: ErrCurrentlyInCreation}
+	s.calls[fid] = call
+	s.m.Unlock()
 
+	defer func() {
 		s.m.Lock()
-		if singleton, exist := s.objects[fid]; exist {
-			s.m.Unlock()
-			if disposer != nil {
-				disposer.Dispose()
-			}
-			return singleton, nil
-		}
+		delete(s.calls, fid)
+		s.m.Unlock()
+		close(call.done)
+	}()
 
-		defer s.m.Unlock()
-
-		if singleton != nil {
-			s.objects[fid] = singleton
-		}
-
-		if disposer != nil {
-			s.disposers[fid] = disposer
-		}
+	obj, disposer, err := createObject()
+	if err != nil {
+		call.err = err
+		return nil, err
+	}
 
-		return singleton, nil
+	s.m.Lock()
+	if obj != nil {
+		s.objects[fid] = obj
+	}
+	if disposer != nil {
+		s.disposers[fid] = disposer
 	}
+	s.m.Unlock()
+
+	call.obj, call.err = obj, nil
+	return obj, nil
 }
 
 func (s *scopeSingleton) Remove(*Factory, any) (any, error) {
@@ -61,7 +86,10 @@ func (s *scopeSingleton) Destroy() {
 	disposers := s.disposers
 	s.disposers = make(map[int]DisposableAdapter)
 	s.objects = make(map[int]any)
-	defer s.m.Unlock()
+	s.calls = make(map[int]*singletonCall)
+	s.m.Unlock()
+
+	// outside the lock, disposers may resolve components
 	for _, disposer := range disposers {
 		disposer.Dispose()
 	}
@@ -0,0 +1,212 @@
+package di
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+type ctxStartupProfilerKeyType int // unexported type for ctxStartupProfilerKey to avoid collisions.
+
+var ctxStartupProfilerKey ctxStartupProfilerKeyType
+
+// StartupReport is a profile of the container initialization (Container.Initialize).
+// Durations are in nanoseconds when exported as JSON.
+//
+// Example:
+//
+//	ctn := di.New(nil, di.WithStartupReport(func(r *di.StartupReport) {
+//		r.WriteTable(os.Stdout)
+//	}))
+type StartupReport struct {
+	Total        time.Duration      `json:"total"`         // total initialization time
+	Components   []*ComponentReport `json:"components"`    // all components created, in creation order
+	CriticalPath []*ComponentReport `json:"critical_path"` // dependency chain with the highest construction time
+	Slowest      []*ComponentReport `json:"slowest"`       // slowest initializers (components created by Initialize)
+	Error        string             `json:"error,omitempty"`
+}
+
+// ComponentReport the creation profile of a component
+type ComponentReport struct {
+	Id           int           `json:"id"`     // factory id
+	Key          string        `json:"key"`    // component key
+	Source       string        `json:"source"` // constructor and location
+	Scope        string        `json:"scope"`
+	Startup      bool          `json:"startup,omitempty"`
+	Start        time.Duration `json:"start"`        // offset from the beginning of the initialization
+	Total        time.Duration `json:"total"`        // Construction + Waiting
+	Construction time.Duration `json:"construction"` // time spent in the constructor and initializers
+	Waiting      time.Duration `json:"waiting"`      // time spent creating the dependencies
+	Dependencies []int         `json:"dependencies,omitempty"`
+	Error        string        `json:"error,omitempty"`
+
+	root          bool               // created directly by the initialization
+	critical      time.Duration      // construction time of the critical path starting at this component
+	criticalChild *ComponentReport   // next component in the critical path
+	children      []*ComponentReport // dependencies created by this component
+}
+
+// WithStartupReport profiles the container initialization. The report is sent to the
+// callback at the end of Container.Initialize (even if the initialization fails).
+func WithStartupReport(callback func(*StartupReport)) ContainerConfig {
+	return func(c *container) {
+		c.startupReport = callback
+	}
+}
+
+// JSON exports the report as indented JSON
+func (r *StartupReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// WriteTable prints the report as text tables
+func (r *StartupReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "STARTUP\t%v\n", r.Total)
+	if r.Error != "" {
+		fmt.Fprintf(tw, "ERROR\t%s\n", r.Error)
+	}
+
+	writeSection := func(title string, components []*ComponentReport) {
+		fmt.Fprintf(tw, "\n%s\n", title)
+		fmt.Fprintln(tw, "COMPONENT\tSCOPE\tSTART\tTOTAL\tCONSTRUCTION\tWAITING\tSOURCE")
+		for _, cr := range components {
+			fmt.Fprintf(tw, "%s\t%s\t%v\t%v\t%v\t%v\t%s\n", cr.Key, cr.Scope, cr.Start, cr.Total, cr.Construction, cr.Waiting, cr.Source)
+		}
+	}
+
+	writeSection("CRITICAL PATH", r.CriticalPath)
+	writeSection("SLOWEST INITIALIZERS", r.Slowest)
+	writeSection("COMPONENTS", r.Components)
+
+	return tw.Flush()
+}
+
+// startupProfiler a Tracer that builds a StartupReport. Propagated by the
+// context, only the components created during the initialization are profiled.
+type startupProfiler struct {
+	m          sync.Mutex
+	start      time.Time
+	components []*ComponentReport
+}
+
+type startupProfilerSpan struct {
+	profiler *startupProfiler
+	report   *ComponentReport
+	start    time.Time
+}
+
+func newStartupProfiler() *startupProfiler {
+	return &startupProfiler{start: time.Now()}
+}
+
+// getStartupProfiler the profiler in the context (see Initialize)
+func getStartupProfiler(ctx context.Context) *startupProfiler {
+	switch v := ctx.Value(ctxStartupProfilerKey).(type) {
+	case *startupProfiler:
+		return v
+	case *startupProfilerSpan:
+		return v.profiler
+	}
+	return nil
+}
+
+func (p *startupProfiler) Start(ctx context.Context, factory *Factory) (context.Context, Span) {
+	span := &startupProfilerSpan{
+		profiler: p,
+		start:    time.Now(),
+		report: &ComponentReport{
+			Id:      factory.id,
+			Key:     factory.String(),
+			Source:  factory.Source(),
+			Scope:   factory.scope,
+			Startup: factory.startup,
+		},
+	}
+	span.report.Start = span.start.Sub(p.start)
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.components = append(p.components, span.report)
+	if parent, ok := ctx.Value(ctxStartupProfilerKey).(*startupProfilerSpan); ok {
+		parent.report.children = append(parent.report.children, span.report)
+		parent.report.Dependencies = append(parent.report.Dependencies, factory.id)
+	} else {
+		span.report.root = true
+	}
+
+	return context.WithValue(ctx, ctxStartupProfilerKey, span), span
+}
+
+func (s *startupProfilerSpan) End(err error) {
+	s.profiler.m.Lock()
+	defer s.profiler.m.Unlock()
+
+	r := s.report
+	r.Total = time.Since(s.start)
+	for _, child := range r.children {
+		r.Waiting += child.Total
+	}
+	r.Construction = r.Total - r.Waiting
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// report builds the StartupReport
+func (p *startupProfiler) report(err error) *StartupReport {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	r := &StartupReport{
+		Total:      time.Since(p.start),
+		Components: p.components,
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+
+	// critical path, children are always created (and reported) after their parent
+	var head *ComponentReport
+	for i := len(p.components) - 1; i >= 0; i-- {
+		cr := p.components[i]
+		cr.critical = cr.Construction
+		cr.criticalChild = nil
+		for _, child := range cr.children {
+			if cr.criticalChild == nil || child.critical > cr.criticalChild.critical {
+				cr.criticalChild = child
+			}
+		}
+		if cr.criticalChild != nil {
+			cr.critical += cr.criticalChild.critical
+		}
+		if head == nil || cr.critical >= head.critical {
+			head = cr
+		}
+	}
+	for cr := head; cr != nil; cr = cr.criticalChild {
+		r.CriticalPath = append(r.CriticalPath, cr)
+	}
+
+	// slowest initializers
+	for _, cr := range p.components {
+		if cr.root {
+			r.Slowest = append(r.Slowest, cr)
+		}
+	}
+	sort.SliceStable(r.Slowest, func(i, j int) bool {
+		return r.Slowest[i].Total > r.Slowest[j].Total
+	})
+	if len(r.Slowest) > 10 {
+		r.Slowest = r.Slowest[:10]
+	}
+
+	return r
+}
@@ -0,0 +1,83 @@
+package di
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartupReport(t *testing.T) {
+	var report *StartupReport
+	ctn := New(nil, WithStartupReport(func(r *StartupReport) {
+		report = r
+	}))
+
+	ctn.Register(func(m *testResolveMiddle) *testResolveRoot {
+		time.Sleep(2 * time.Millisecond)
+		return &testResolveRoot{}
+	}, Startup(100))
+	ctn.Register(func(l *testResolveLeaf) *testResolveMiddle {
+		return &testResolveMiddle{}
+	})
+	ctn.Register(func() *testResolveLeaf {
+		time.Sleep(10 * time.Millisecond)
+		return &testResolveLeaf{}
+	})
+	ctn.Register(func() *testCycleA { return &testCycleA{} })
+	ctn.Register(func() testServiceA {
+		time.Sleep(time.Millisecond)
+		return newTestServiceA("a", nil)
+	}, Startup(200))
+
+	require.NoError(t, ctn.Initialize())
+	require.NotNil(t, report)
+	require.Empty(t, report.Error)
+	require.Len(t, report.Components, 4)
+
+	root, middle, leaf, service := report.Components[0], report.Components[1], report.Components[2], report.Components[3]
+	require.Equal(t, "*di.testResolveRoot", root.Key)
+	require.Equal(t, []int{middle.Id}, root.Dependencies)
+	require.Equal(t, []int{leaf.Id}, middle.Dependencies)
+	require.Equal(t, root.Total, root.Construction+root.Waiting)
+	require.Equal(t, middle.Total, root.Waiting)
+	require.GreaterOrEqual(t, leaf.Construction, 10*time.Millisecond)
+
+	require.Equal(t, []*ComponentReport{root, middle, leaf}, report.CriticalPath)
+	require.Equal(t, []*ComponentReport{root, service}, report.Slowest)
+
+	data, err := report.JSON()
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded["critical_path"], 3)
+
+	var table bytes.Buffer
+	require.NoError(t, report.WriteTable(&table))
+	require.Contains(t, table.String(), "CRITICAL PATH")
+	require.Contains(t, table.String(), "*di.testResolveLeaf")
+
+	// components created after the initialization are not profiled
+	_, err = GetFrom[*testCycleA](ctn)
+	require.NoError(t, err)
+	require.Len(t, report.Components, 4)
+}
+
+func TestStartupReportError(t *testing.T) {
+	var report *StartupReport
+	ctn := New(nil, WithStartupReport(func(r *StartupReport) {
+		report = r
+	}))
+
+	errFail := errors.New("failed")
+	ctn.Register(func() (testServiceA, error) { return nil, errFail }, Startup(100))
+
+	require.ErrorIs(t, ctn.Initialize(), errFail)
+	require.NotNil(t, report)
+	require.Contains(t, report.Error, "failed")
+	require.Len(t, report.Components, 1)
+	require.Contains(t, report.Components[0].Error, "failed")
+}
@@ -0,0 +1,36 @@
+package di
+
+import (
+	"context"
+)
+
+// Tracer receives a Span for each component created by the container (constructor
+// call), during the initialization or lazily (Get). Spans are nested along the
+// dependency path: the dependencies of a component are created with the context
+// returned by Start.
+//
+// Example:
+//
+//	ctn := di.New(nil, di.WithTracer(myTracer))
+//
+// See github.com/go-path/di/diotel for an OpenTelemetry adapter.
+type Tracer interface {
+	// Start is invoked before the dependencies of the factory are resolved. The
+	// returned context is used to resolve the dependencies and is passed to the
+	// constructor (if it accepts a context.Context).
+	Start(ctx context.Context, factory *Factory) (context.Context, Span)
+}
+
+// Span represents the creation of a component
+type Span interface {
+	// End is invoked after the constructor and the initializers of the component.
+	// err is not nil if the component could not be created.
+	End(err error)
+}
+
+// WithTracer sets the Tracer of the container
+func WithTracer(tracer Tracer) ContainerConfig {
+	return func(c *container) {
+		c.tracer = tracer
+	}
+}
@@ -0,0 +1,75 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSpanKey struct{}
+
+type testSpan struct {
+	name   string
+	parent *testSpan
+	ended  bool
+	err    error
+}
+
+func (s *testSpan) End(err error) {
+	s.ended = true
+	s.err = err
+}
+
+// testTracer in-memory recorder
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, factory *Factory) (context.Context, Span) {
+	parent, _ := ctx.Value(testSpanKey{}).(*testSpan)
+	span := &testSpan{name: factory.String(), parent: parent}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func TestTracer(t *testing.T) {
+	tracer := &testTracer{}
+	ctn := New(nil, WithTracer(tracer))
+
+	errLeaf := errors.New("leaf failed")
+	var spanInConstructor *testSpan
+
+	ctn.Register(func(m *testResolveMiddle) *testResolveRoot { return &testResolveRoot{} })
+	ctn.Register(func(ctx context.Context, l *testResolveLeaf) *testResolveMiddle {
+		spanInConstructor, _ = ctx.Value(testSpanKey{}).(*testSpan)
+		return &testResolveMiddle{}
+	})
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ctn.Register(func() (testServiceA, error) { return nil, errLeaf }, Startup(100))
+
+	require.ErrorIs(t, ctn.Initialize(), errLeaf)
+	require.Len(t, tracer.spans, 1)
+	require.ErrorIs(t, tracer.spans[0].err, errLeaf)
+
+	_, err := GetFrom[*testResolveRoot](ctn)
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 4)
+
+	root, middle, leaf := tracer.spans[1], tracer.spans[2], tracer.spans[3]
+	require.Equal(t, "*di.testResolveRoot", root.name)
+	require.Nil(t, root.parent)
+	require.Equal(t, root, middle.parent)
+	require.Equal(t, middle, leaf.parent)
+	require.Equal(t, middle, spanInConstructor)
+	for _, span := range tracer.spans[1:] {
+		require.True(t, span.ended)
+		require.NoError(t, span.err)
+	}
+
+	// cached singletons are not traced
+	_, err = GetFrom[*testResolveRoot](ctn)
+	require.NoError(t, err)
+	require.Len(t, tracer.spans, 4)
+}
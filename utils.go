@@ -2,7 +2,10 @@ package di
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"runtime"
+	"testing"
 )
 
 var (
@@ -13,6 +16,7 @@ var (
 	_typeDisposable  = Key[Disposable]()
 	_typeInitializer = Key[Initializable]()
 	_typeReflectType = Key[reflect.Type]()
+	_typeWorker      = Key[Worker]()
 )
 
 func isError(t reflect.Type) bool {
@@ -31,6 +35,29 @@ func getContext(contexts ...context.Context) (ctx context.Context) {
 	return context.Background()
 }
 
+// funcName returns the name of the function (ex. "github.com/go-path/di/examples.NewService")
+func funcName(fn reflect.Value) string {
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return ""
+	}
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		return f.Name()
+	}
+	return fn.Type().String()
+}
+
+// funcSource returns the name and source location of the function (ex. "pkg.NewService (/src/service.go:12)")
+func funcSource(fn reflect.Value) string {
+	if fn.Kind() != reflect.Func || fn.IsNil() {
+		return ""
+	}
+	if f := runtime.FuncForPC(fn.Pointer()); f != nil {
+		file, line := f.FileLine(f.Entry())
+		return fmt.Sprintf("%s (%s:%d)", f.Name(), file, line)
+	}
+	return ""
+}
+
 // Key is a pointer to a type
 func Key[T any]() reflect.Type {
 	return reflect.TypeOf((*T)(nil)).Elem()
@@ -80,6 +107,62 @@ func MustGetFrom[T any](c Container, ctx ...context.Context) T {
 	return o
 }
 
+// MockFor mocks the component T of the container, interfaces included (see Container.Mock).
+// Accepts a T, a "func() T", a "func(context.Context) T" or a "func(context.Context) (T, error)".
+//
+// Example:
+//
+//	cleanup := di.MockFor[Repository](ctn, &FakeRepository{})
+//	defer cleanup()
+func MockFor[T any](c Container, mock any) (cleanup func()) {
+	ctn, ok := c.(*container)
+	if !ok {
+		panic(fmt.Errorf("MockFor: unsupported container %T", c))
+	}
+
+	var fn mockFunc
+	switch m := mock.(type) {
+	case T:
+		fn = func(ctx context.Context) (any, error) { return m, nil }
+	case func() T:
+		fn = func(ctx context.Context) (any, error) { return m(), nil }
+	case func(context.Context) T:
+		fn = func(ctx context.Context) (any, error) { return m(ctx), nil }
+	case func(context.Context) (T, error):
+		fn = func(ctx context.Context) (any, error) { return m(ctx) }
+	default:
+		panic(fmt.Errorf("MockFor: %T is not a %v", mock, Key[T]()))
+	}
+	return ctn.mock(Key[T](), fn)
+}
+
+// FactoryOf returns the factory selected by the container to create the component T, the
+// same one used by Get (strict mode, Primary and Alternative included). Fails with
+// ErrManyCandidates when T is ambiguous and ErrCandidateNotFound when it is missing.
+func FactoryOf[T any](c Container) (*Factory, error) {
+	key := Key[T]()
+	ctn, ok := c.(*container)
+	if !ok {
+		panic(fmt.Errorf("FactoryOf: unsupported container %T", c))
+	}
+	for ctn.parent != nil && !ctn.Contains(key) {
+		parent, ok := ctn.parent.(*container)
+		if !ok {
+			panic(fmt.Errorf("FactoryOf: unsupported container %T", ctn.parent))
+		}
+		ctn = parent
+	}
+	return ctn.factoryFor(key, context.Background())
+}
+
+// VerifyFrom fails the test if the container has wiring errors (see Container.Validate)
+func VerifyFrom(t testing.TB, c Container) {
+	t.Helper()
+	if err := c.Validate(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func FilterOf[T any](c Container) *FilteredFactories {
 	key := Key[T]()
 	cond := Condition(func(c Container, f *Factory) bool {
@@ -93,10 +176,15 @@ func AllOf[T any](c Container, ctx context.Context) (o []T, e error) {
 	return AllOfFilter[T](FilterOf[T](c), ctx)
 }
 
+// AllOfFilter the instances of the filtered components. The private components of
+// other modules are skipped (see Private).
 func AllOfFilter[T any](filter *FilteredFactories, ctx context.Context) (o []T, e error) {
 	var objects []T
 
 	err := filter.Foreach(func(f *Factory) (bool, error) {
+		if checkVisibility(f.key, f, ctx) != nil {
+			return false, nil
+		}
 		if obj, disposer, err := filter.container.GetObjectFactory(f, true, ctx)(); err != nil {
 			return true, err
 		} else if o, ok := obj.(T); ok {
@@ -0,0 +1,136 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// Wiring creates the component of a constructor: resolves its dependencies with typed
+// calls (see Resolve, ResolveProvider, ResolveUnmanaged and ResolveQualified) and calls
+// the constructor directly. Wirings are generated by cmd/di-gen.
+type Wiring func(ctn Container, ctx context.Context) (any, error)
+
+// wirings generated wirings by constructor (function pointer)
+var wirings sync.Map
+
+// RegisterWiring registers the Wiring of a constructor (top-level function). The
+// container uses it instead of resolving the arguments and calling the constructor
+// through reflection, no matter if the constructor was registered before or after the
+// wiring. Scopes, qualifiers, mocks and the other features of the container still
+// apply, the dependencies are resolved by the container.
+//
+// Invoked by the code generated by cmd/di-gen, not meant to be called directly.
+func RegisterWiring(ctor any, wiring Wiring) {
+	if reflect.TypeOf(ctor).Kind() != reflect.Func {
+		panic(fmt.Errorf("RegisterWiring: %T is not a function", ctor))
+	}
+	wirings.Store(reflectPointer(ctor), wiring)
+}
+
+// Resolve resolves the dependency T of a Wiring
+func Resolve[T any](ctn Container, ctx context.Context) (T, error) {
+	v, err := ctn.Get(Key[T](), ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return typed[T](v)
+}
+
+// ResolveProvider resolves the dependency Provider[T] of a Wiring
+func ResolveProvider[T any](ctn Container, ctx context.Context) (p Provider[T], err error) {
+	key := Key[T]()
+	if c, ok := ctn.(*container); ok {
+		if err = c.checkProviderVisibility(key, ctx); err != nil {
+			return
+		}
+	}
+	// managed by scope (Ex. Request Scoped will destroy any Scoped("request"))
+	objectFactory := ctn.GetObjectFactoryFor(key, true)
+	return p.With(func() (any, error) {
+		object, _, err := objectFactory()
+		return object, err
+	}), nil
+}
+
+// ResolveUnmanaged resolves the dependency Unmanaged[T] of a Wiring
+func ResolveUnmanaged[T any](ctn Container, ctx context.Context) (u Unmanaged[T], err error) {
+	key := Key[T]()
+	if c, ok := ctn.(*container); ok {
+		if err = c.checkProviderVisibility(key, ctx); err != nil {
+			return
+		}
+	}
+	// user will be responsible for cleaning them up (call disposable.Dispose())
+	objectFactory := ctn.GetObjectFactoryFor(key, false)
+	return u.With(objectFactory), nil
+}
+
+// ResolveQualified resolves the dependency Qualified[T, Q] of a Wiring
+func ResolveQualified[T any, Q any](ctn Container, ctx context.Context) (q Qualified[T, Q], err error) {
+	var v T
+	if v, err = Resolve[T](ctn, ctx); err != nil {
+		return
+	}
+	return q.With(v), nil
+}
+
+// typed the component as T, a nil component is the zero value of T
+func typed[T any](v any) (out T, err error) {
+	if v == nil {
+		return
+	}
+	out, ok := v.(T)
+	if !ok {
+		err = fmt.Errorf("the component %T is not a %v", v, Key[T]())
+	}
+	return
+}
+
+// RegisterInjector registers the injector of T, used by Injector[T] instead of the
+// reflection based injector (see InjectorOf).
+//
+// Invoked by the code generated by cmd/di-gen, not meant to be called directly.
+func RegisterInjector[T any](injector func(Container, context.Context) (T, error)) {
+	structType := reflect.TypeOf((*T)(nil)).Elem()
+
+	injectorsMu.Lock()
+	defer injectorsMu.Unlock()
+	injectors[structType] = func(ctn Container, ctx context.Context) (any, error) {
+		out, err := injector(ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+}
+
+// Inject resolves a field of type F of the struct T (`inject` tag), see Injector.
+//
+// Invoked by the code generated by cmd/di-gen, not meant to be called directly.
+func Inject[F any, T any](ctn Container, ctx context.Context) (F, error) {
+	v, err := resolveField(ctn, ctx, reflect.TypeOf((*F)(nil)).Elem(), reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		var zero F
+		return zero, err
+	}
+	return typed[F](v)
+}
+
+// getWiring the generated wiring of the factory, if any
+func (f *Factory) getWiring() Wiring {
+	if f.isReference || !f.factoryValue.IsValid() || f.factoryValue.Kind() != reflect.Func {
+		return nil
+	}
+	if wiring, ok := wirings.Load(f.factoryValue.Pointer()); ok {
+		return wiring.(Wiring)
+	}
+	return nil
+}
+
+// reflectPointer the code pointer of the function
+func reflectPointer(fn any) uintptr {
+	return reflect.ValueOf(fn).Pointer()
+}
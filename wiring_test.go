@@ -0,0 +1,165 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testWiringService struct {
+	leaf *testResolveLeaf
+	by   string
+}
+
+func newTestWiringService(leaf *testResolveLeaf) (*testWiringService, error) {
+	if leaf == nil {
+		return nil, errors.New("leaf required")
+	}
+	return &testWiringService{leaf: leaf, by: "reflection"}, nil
+}
+
+type testInjectedController struct {
+	Service *testWiringService `inject:""`
+	by      string
+}
+
+type testWiredService struct {
+	leaf   *testResolveLeaf
+	root   Provider[*testResolveRoot]
+	middle Unmanaged[*testResolveMiddle]
+	a      Qualified[testServiceA, testQualifierA]
+	ctx    context.Context
+	by     string
+}
+
+func newTestWiredService(ctx context.Context, leaf *testResolveLeaf, root Provider[*testResolveRoot],
+	middle Unmanaged[*testResolveMiddle], a Qualified[testServiceA, testQualifierA]) *testWiredService {
+	return &testWiredService{ctx: ctx, leaf: leaf, root: root, middle: middle, a: a, by: "reflection"}
+}
+
+func TestWiring(t *testing.T) {
+	ctn := New(nil)
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ctn.Register(newTestWiringService)
+
+	// generated code, registered after the constructor (init order)
+	RegisterWiring(newTestWiringService, func(ctn Container, ctx context.Context) (any, error) {
+		a0, err := Resolve[*testResolveLeaf](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		v, err := newTestWiringService(a0)
+		if err != nil {
+			return nil, err
+		}
+		v.by = "wiring"
+		return v, nil
+	})
+	t.Cleanup(func() { wirings.Delete(reflectPointer(newTestWiringService)) })
+
+	require.NoError(t, ctn.Initialize())
+	s, err := GetFrom[*testWiringService](ctn)
+	require.NoError(t, err)
+	require.Equal(t, "wiring", s.by)
+	require.NotNil(t, s.leaf)
+
+	require.Panics(t, func() { RegisterWiring(1, nil) })
+}
+
+func TestWiringResolve(t *testing.T) {
+	middles := 0
+	ctn := New(nil)
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ctn.Register(func() *testResolveRoot { return &testResolveRoot{} })
+	ctn.Register(func() *testResolveMiddle { middles++; return &testResolveMiddle{} })
+	ctn.Register(func() testServiceA { return newTestServiceA("a", nil) }, Qualify[testQualifierA]())
+	ctn.Register(newTestWiredService)
+
+	RegisterWiring(newTestWiredService, func(ctn Container, ctx context.Context) (any, error) {
+		a1, err := Resolve[*testResolveLeaf](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		a2, err := ResolveProvider[*testResolveRoot](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		a3, err := ResolveUnmanaged[*testResolveMiddle](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		a4, err := ResolveQualified[testServiceA, testQualifierA](ctn, ctx)
+		if err != nil {
+			return nil, err
+		}
+		v := newTestWiredService(ctx, a1, a2, a3, a4)
+		v.by = "wiring"
+		return v, nil
+	})
+	t.Cleanup(func() { wirings.Delete(reflectPointer(newTestWiredService)) })
+
+	require.NoError(t, ctn.Initialize())
+	s := MustGetFrom[*testWiredService](ctn)
+	require.Equal(t, "wiring", s.by)
+	require.NotNil(t, s.ctx)
+	require.Same(t, MustGetFrom[*testResolveLeaf](ctn), s.leaf)
+
+	root, err := s.root.Get()
+	require.NoError(t, err)
+	require.Same(t, MustGetFrom[*testResolveRoot](ctn), root, "managed")
+
+	MustGetFrom[*testResolveMiddle](ctn)
+	middle, disposer, err := s.middle.Get()
+	require.NoError(t, err)
+	require.NotNil(t, middle)
+	require.NotNil(t, disposer)
+	require.Equal(t, 2, middles, "unmanaged")
+
+	require.Equal(t, "a", s.a.Get().Name())
+}
+
+func TestWiringTyped(t *testing.T) {
+	leaf, err := typed[*testResolveLeaf](&testResolveLeaf{})
+	require.NoError(t, err)
+	require.NotNil(t, leaf)
+
+	a, err := typed[testServiceA](nil)
+	require.NoError(t, err)
+	require.Nil(t, a)
+
+	_, err = typed[*testResolveLeaf](&testResolveRoot{})
+	require.EqualError(t, err, "the component *di.testResolveRoot is not a *di.testResolveLeaf")
+}
+
+func TestRegisterInjector(t *testing.T) {
+	ctn := New(nil)
+	ctn.Register(func() *testResolveLeaf { return &testResolveLeaf{} })
+	ctn.Register(newTestWiringService)
+	InjectedTo[*testInjectedController](ctn)
+
+	// generated code, registered after the reflection based injector
+	RegisterInjector(func(ctn Container, ctx context.Context) (out *testInjectedController, err error) {
+		out = &testInjectedController{by: "generated"}
+		if out.Service, err = Inject[*testWiringService, *testInjectedController](ctn, ctx); err != nil {
+			return
+		}
+		return
+	})
+	t.Cleanup(func() {
+		injectorsMu.Lock()
+		delete(injectors, Key[*testInjectedController]())
+		injectorsMu.Unlock()
+	})
+
+	require.NoError(t, ctn.Initialize())
+	c, err := GetFrom[*testInjectedController](ctn)
+	require.NoError(t, err)
+	require.Equal(t, "generated", c.by)
+	require.NotNil(t, c.Service)
+
+	// missing dependency
+	_, err = Inject[testServiceA, *testInjectedController](ctn, context.Background())
+	require.ErrorIs(t, err, ErrCandidateNotFound)
+}
@@ -0,0 +1,265 @@
+package di
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Worker a long-running component (Ex. server, queue consumer). The container starts
+// every Worker in a supervised goroutine at the end of Initialize, and cancels the context
+// on Destroy (see WithShutdownTimeout). Run must return when the context is canceled.
+//
+// Example:
+//
+//	type Server struct {
+//		Router *Router `inject:""`
+//	}
+//
+//	func (s *Server) Run(ctx context.Context) error {
+//		server := &http.Server{Addr: ":8080", Handler: s.Router}
+//		go func() {
+//			<-ctx.Done()
+//			server.Shutdown(context.Background())
+//		}()
+//		if err := server.ListenAndServe(); err != http.ErrServerClosed {
+//			return err
+//		}
+//		return nil
+//	}
+//
+//	di.Injected[*Server](di.RestartOnError(time.Second, time.Minute))
+type Worker interface {
+	Run(ctx context.Context) error
+}
+
+// WorkerState the state of a Worker (see WorkerStatus)
+type WorkerState string
+
+const (
+	WorkerRunning    WorkerState = "running"
+	WorkerRestarting WorkerState = "restarting" // waiting the backoff (see RestartOnError)
+	WorkerStopped    WorkerState = "stopped"    // Run returned nil or the container was destroyed
+	WorkerFailed     WorkerState = "failed"     // Run returned an error (or panicked)
+)
+
+// WorkerStatus the status of a Worker (see Container.Workers)
+type WorkerStatus struct {
+	Factory  *Factory
+	State    WorkerState
+	Restarts int
+	Started  time.Time // last start
+	Err      error     // last error
+}
+
+// workerBackoff see RestartOnError
+type workerBackoff struct {
+	initial time.Duration
+	max     time.Duration
+}
+
+// RestartOnError restarts the Worker when Run returns an error (or panics). The delay
+// before each restart starts at initial and doubles on consecutive failures, up to max.
+// It is reset when the worker runs for longer than max. A non-positive initial delay is
+// replaced by 1ms, max is at least initial.
+func RestartOnError(initial, max time.Duration) FactoryConfig {
+	if initial <= 0 {
+		initial = time.Millisecond
+	}
+	if max < initial {
+		max = initial
+	}
+	return func(f *Factory) {
+		f.restart = &workerBackoff{initial: initial, max: max}
+	}
+}
+
+// WithShutdownTimeout the time Destroy waits for the workers to return (see Worker) and
+// for the async event listeners (see Async). Defaults to 30 seconds. After the timeout,
+// Destroy returns ErrShutdownTimeout but still disposes the components, the workers
+// still running may use disposed components.
+func WithShutdownTimeout(timeout time.Duration) ContainerConfig {
+	return func(c *container) {
+		c.shutdownTimeout = timeout
+	}
+}
+
+// worker a Worker started by the container
+type worker struct {
+	mu     sync.Mutex
+	run    Worker
+	clock  Clock // see WithClock
+	status WorkerStatus
+}
+
+func (w *worker) set(state WorkerState, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.State = state
+	w.status.Err = err
+	if state == WorkerRunning {
+		w.status.Started = w.clock.Now()
+	}
+}
+
+// runSafe runs the worker, panics are returned as errors
+func (w *worker) runSafe(ctx context.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("worker panic: %v", r)
+		}
+	}()
+	return w.run.Run(ctx)
+}
+
+// Workers the status of the workers started by Initialize, by Order
+func (c *container) Workers() []WorkerStatus {
+	c.workersMu.Lock()
+	defer c.workersMu.Unlock()
+
+	statuses := make([]WorkerStatus, len(c.workers))
+	for i, w := range c.workers {
+		w.mu.Lock()
+		statuses[i] = w.status
+		w.mu.Unlock()
+	}
+	return statuses
+}
+
+// startWorkers creates and starts the workers and the Scheduled components of this
+// container (see Initialize)
+func (c *container) startWorkers(ctx context.Context) error {
+	var factories []*Factory
+	for _, list := range c.factories {
+		for _, f := range list {
+			if f.schedule != nil || (f.ReturnsValue() && f.Type().Implements(_typeWorker)) {
+				factories = append(factories, f)
+			}
+		}
+	}
+	if len(factories) == 0 {
+		return nil
+	}
+	sort.Slice(factories, func(i, j int) bool {
+		if factories[i].order != factories[j].order {
+			return factories[i].order < factories[j].order
+		}
+		return factories[i].id < factories[j].id
+	})
+
+	// all the workers are created before starting any, a creation error leaves none running
+	var workers []*worker
+	for _, f := range factories {
+		w := &worker{clock: c.clock, status: WorkerStatus{Factory: f}}
+		if f.schedule != nil {
+			w.run = &scheduledTask{c: c, factory: f, worker: w}
+		} else {
+			instance, _, err := c.GetObjectFactory(f, true, ctx)()
+			if err != nil {
+				return err
+			}
+			run, ok := instance.(Worker)
+			if !ok {
+				// nil
+				continue
+			}
+			w.run = run
+		}
+		workers = append(workers, w)
+	}
+
+	c.workersMu.Lock()
+	defer c.workersMu.Unlock()
+
+	ctx, c.workersCancel = context.WithCancel(context.WithoutCancel(ctx))
+	for _, w := range workers {
+		c.workers = append(c.workers, w)
+		c.workersWg.Add(1)
+		go c.supervise(ctx, w)
+	}
+	return nil
+}
+
+// supervise runs the worker until it returns, restarting it on error (see RestartOnError)
+func (c *container) supervise(ctx context.Context, w *worker) {
+	defer c.workersWg.Done()
+
+	f := w.status.Factory
+	var delay time.Duration
+	if f.restart != nil {
+		delay = f.restart.initial
+	}
+
+	for {
+		w.set(WorkerRunning, nil)
+		start := c.clock.Now()
+		err := w.runSafe(ctx)
+		if ctx.Err() != nil || err == nil {
+			w.set(WorkerStopped, err)
+			return
+		}
+
+		c.log(LogError, "[di] worker failed", slog.String("worker", f.String()), slog.Any("error", err))
+		if f.restart == nil {
+			w.set(WorkerFailed, err)
+			return
+		}
+
+		if c.clock.Now().Sub(start) > f.restart.max {
+			delay = f.restart.initial
+		}
+		w.set(WorkerRestarting, err)
+		if !c.clock.Sleep(ctx, delay) {
+			w.set(WorkerStopped, err)
+			return
+		}
+		delay = min(delay*2, f.restart.max)
+
+		w.mu.Lock()
+		w.status.Restarts++
+		w.mu.Unlock()
+	}
+}
+
+// stopWorkers cancels the workers and waits for them until the deadline (see
+// WithShutdownTimeout)
+func (c *container) stopWorkers(deadline time.Time) error {
+	c.workersMu.Lock()
+	cancel := c.workersCancel
+	c.workersMu.Unlock()
+	if cancel == nil {
+		return nil
+	}
+	cancel()
+
+	if waitUntil(&c.workersWg, deadline) {
+		return nil
+	}
+	var running []string
+	for _, status := range c.Workers() {
+		if status.State == WorkerRunning {
+			running = append(running, status.Factory.String())
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrShutdownTimeout, strings.Join(running, ", "))
+}
+
+// waitUntil waits for the WaitGroup until the deadline, false on timeout
+func waitUntil(wg *sync.WaitGroup, deadline time.Time) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(time.Until(deadline)):
+		return false
+	}
+}
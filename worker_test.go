@@ -0,0 +1,131 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testWorker struct {
+	runs    atomic.Int32
+	fail    int32 // runs that fail
+	started chan struct{}
+}
+
+func (w *testWorker) Run(ctx context.Context) error {
+	if w.runs.Add(1) <= w.fail {
+		return errors.New("failed")
+	}
+	close(w.started)
+	<-ctx.Done()
+	return nil
+}
+
+type testStuckWorker struct{}
+
+func (w *testStuckWorker) Run(ctx context.Context) error {
+	select {}
+}
+
+type testPanicWorker struct{}
+
+func (w *testPanicWorker) Run(ctx context.Context) error {
+	panic("boom")
+}
+
+// testFixedClock the time does not move
+type testFixedClock struct{ now time.Time }
+
+func (c testFixedClock) Now() time.Time { return c.now }
+
+func (c testFixedClock) Sleep(ctx context.Context, d time.Duration) bool {
+	return realClock{}.Sleep(ctx, d)
+}
+
+func TestWorker(t *testing.T) {
+	t.Run("run and stop", func(t *testing.T) {
+		w := &testWorker{started: make(chan struct{})}
+		ctn := New(nil)
+		ctn.Register(w)
+		require.NoError(t, ctn.Initialize())
+		<-w.started
+
+		statuses := ctn.Workers()
+		require.Len(t, statuses, 1)
+		require.Equal(t, WorkerRunning, statuses[0].State)
+		require.Equal(t, "*di.testWorker", statuses[0].Factory.String())
+
+		require.NoError(t, ctn.Destroy())
+		require.Equal(t, WorkerStopped, ctn.Workers()[0].State)
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		w := &testWorker{started: make(chan struct{}), fail: 2}
+		ctn := New(nil)
+		ctn.Register(w, RestartOnError(time.Millisecond, 10*time.Millisecond))
+		require.NoError(t, ctn.Initialize())
+		<-w.started
+
+		status := ctn.Workers()[0]
+		require.Equal(t, WorkerRunning, status.State)
+		require.Equal(t, 2, status.Restarts)
+		require.NoError(t, ctn.Destroy())
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		ctn := New(nil)
+		ctn.Register(&testPanicWorker{})
+		require.NoError(t, ctn.Initialize())
+
+		require.Eventually(t, func() bool {
+			return ctn.Workers()[0].State == WorkerFailed
+		}, time.Second, time.Millisecond)
+		require.ErrorContains(t, ctn.Workers()[0].Err, "worker panic: boom")
+		require.NoError(t, ctn.Destroy())
+	})
+
+	t.Run("creation error", func(t *testing.T) {
+		w := &testWorker{started: make(chan struct{})}
+		ctn := New(nil)
+		ctn.Register(w, Order(1))
+		ctn.Register(func() (*testStuckWorker, error) { return nil, errors.New("boom") }, Order(2))
+		require.ErrorContains(t, ctn.Initialize(), "boom")
+
+		// no worker is started
+		require.Empty(t, ctn.Workers())
+		require.Zero(t, w.runs.Load())
+	})
+
+	t.Run("clock", func(t *testing.T) {
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		w := &testWorker{started: make(chan struct{})}
+		ctn := New(nil, WithClock(testFixedClock{now}))
+		ctn.Register(w)
+		require.NoError(t, ctn.Initialize())
+		<-w.started
+
+		require.Equal(t, now, ctn.Workers()[0].Started)
+		require.NoError(t, ctn.Destroy())
+	})
+
+	t.Run("restart delay", func(t *testing.T) {
+		ctn := New(nil)
+		ctn.Register(&testPanicWorker{}, RestartOnError(0, 0))
+		f := ctn.(*container).factories[Key[*testPanicWorker]()][0]
+		require.Equal(t, workerBackoff{initial: time.Millisecond, max: time.Millisecond}, *f.restart)
+	})
+
+	t.Run("shutdown timeout", func(t *testing.T) {
+		ctn := New(nil, WithShutdownTimeout(10*time.Millisecond))
+		ctn.Register(&testStuckWorker{})
+		require.NoError(t, ctn.Initialize())
+
+		err := ctn.Destroy()
+		require.ErrorIs(t, err, ErrShutdownTimeout)
+		require.ErrorContains(t, err, "*di.testStuckWorker")
+	})
+}